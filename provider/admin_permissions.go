@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// AdminPermissionScope configures the permission Keycloak creates for one fine-grained admin scope
+type AdminPermissionScope struct {
+	Policies         []string `pulumi:"policies"`
+	DecisionStrategy *string  `pulumi:"decisionStrategy,optional"`
+	Description      *string  `pulumi:"description,optional"`
+}
+
+func (s *AdminPermissionScope) Annotate(a infer.Annotator) {
+	a.Describe(&s.Policies, "IDs of the realm-management authorization policies granting this scope")
+	a.Describe(&s.DecisionStrategy, "How the policies are combined: UNANIMOUS, AFFIRMATIVE or CONSENSUS")
+	a.Describe(&s.Description, "Description of the scope permission")
+
+	a.SetDefault(&s.DecisionStrategy, "UNANIMOUS")
+}
+
+// realmManagementClientID is the client holding the authorization settings for fine-grained admin permissions
+const realmManagementClientID = "realm-management"
+
+// setManagementPermissions enables or disables fine-grained admin permissions at the given endpoint
+// and returns the permission IDs keyed by scope name
+func setManagementPermissions(ctx context.Context, client KeycloakClient, token, url string, enabled bool) (map[string]string, error) {
+	var result gocloak.ManagementPermissionRepresentation
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetBody(gocloak.ManagementPermissionRepresentation{Enabled: &enabled}).
+		SetResult(&result).
+		Put(url)
+	if err := checkResponse(resp, err); err != nil {
+		return nil, fmt.Errorf("failed to set management permissions: %w", err)
+	}
+
+	if result.ScopePermissions == nil {
+		return nil, nil
+	}
+	return *result.ScopePermissions, nil
+}
+
+// getManagementPermissions returns the scope permission IDs at the given endpoint, or nil when permissions are disabled
+func getManagementPermissions(ctx context.Context, client KeycloakClient, token, url string) (map[string]string, error) {
+	var result gocloak.ManagementPermissionRepresentation
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetResult(&result).
+		Get(url)
+	if err := checkResponse(resp, err); err != nil {
+		return nil, err
+	}
+
+	if !gocloak.PBool(result.Enabled) || result.ScopePermissions == nil {
+		return nil, nil
+	}
+	return *result.ScopePermissions, nil
+}
+
+// realmManagementClient returns the internal ID of the realm-management client
+func realmManagementClient(ctx context.Context, client KeycloakClient, token, realmName string) (string, error) {
+	return clientUUID(ctx, client, token, realmName, realmManagementClientID)
+}
+
+// applyScopePermissions attaches the configured policies to each scope permission
+func applyScopePermissions(ctx context.Context, client KeycloakClient, token, realmName string, permissionIDs map[string]string, scopes map[string]*AdminPermissionScope) error {
+	idOfClient, err := realmManagementClient(ctx, client, token, realmName)
+	if err != nil {
+		return err
+	}
+
+	for scope, config := range scopes {
+		if config == nil {
+			continue
+		}
+		permissionID, ok := permissionIDs[scope]
+		if !ok {
+			return fmt.Errorf("scope %s is not supported by the server", scope)
+		}
+
+		url := adminRealmURL(ctx, realmName, "clients", idOfClient, "authz", "resource-server", "permission", "scope", permissionID)
+
+		var permission map[string]interface{}
+		resp, err := client.GetRequestWithBearerAuth(ctx, token).
+			SetResult(&permission).
+			Get(url)
+		if err := checkResponse(resp, err); err != nil {
+			return fmt.Errorf("failed to get %s permission: %w", scope, err)
+		}
+
+		permission["policies"] = config.Policies
+		if config.DecisionStrategy != nil {
+			permission["decisionStrategy"] = *config.DecisionStrategy
+		}
+		if config.Description != nil {
+			permission["description"] = *config.Description
+		}
+
+		resp, err = client.GetRequestWithBearerAuth(ctx, token).
+			SetBody(permission).
+			Put(url)
+		if err := checkResponse(resp, err); err != nil {
+			return fmt.Errorf("failed to update %s permission: %w", scope, err)
+		}
+	}
+
+	return nil
+}
+
+// importedScopes marks every scope as configured, so that an import, which has no configured scopes, reads all the
+// scopes Keycloak has a permission for
+func importedScopes(scopes map[string]*AdminPermissionScope) map[string]*AdminPermissionScope {
+	imported := make(map[string]*AdminPermissionScope, len(scopes))
+	for scope := range scopes {
+		imported[scope] = &AdminPermissionScope{}
+	}
+	return imported
+}
+
+// readScopePermissions reads back the policies of the scopes that are configured
+func readScopePermissions(ctx context.Context, client KeycloakClient, token, realmName string, permissionIDs map[string]string, scopes map[string]*AdminPermissionScope) (map[string]*AdminPermissionScope, error) {
+	idOfClient, err := realmManagementClient(ctx, client, token, realmName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*AdminPermissionScope, len(scopes))
+	for scope, config := range scopes {
+		permissionID, ok := permissionIDs[scope]
+		if config == nil || !ok {
+			continue
+		}
+
+		permission, err := client.GetPolicy(ctx, token, realmName, idOfClient, permissionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s permission: %w", scope, err)
+		}
+		policies, err := client.GetAuthorizationPolicyAssociatedPolicies(ctx, token, realmName, idOfClient, permissionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s permission policies: %w", scope, err)
+		}
+
+		state := &AdminPermissionScope{
+			Policies:    []string{},
+			Description: permission.Description,
+		}
+		if permission.DecisionStrategy != nil {
+			strategy := string(*permission.DecisionStrategy)
+			state.DecisionStrategy = &strategy
+		}
+		for _, policy := range policies {
+			state.Policies = append(state.Policies, gocloak.PString(policy.ID))
+		}
+		result[scope] = state
+	}
+
+	return result, nil
+}
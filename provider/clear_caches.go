@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// ClearRealmCache clears the realm cache of a realm
+type ClearRealmCache struct{}
+
+// ClearUserCache clears the user cache of a realm
+type ClearUserCache struct{}
+
+// ClearKeysCache clears the cache of external public keys of a realm
+type ClearKeysCache struct{}
+
+type ClearCacheArgs struct {
+	RealmID string `pulumi:"realmId"`
+}
+
+type ClearCacheResult struct {
+	Cache string `pulumi:"cache"`
+}
+
+// Annotate provides schema documentation for the clearRealmCache function
+func (c *ClearRealmCache) Annotate(a infer.Annotator) {
+	a.Describe(&c, "Clears the realm cache, so changes to realms, clients, roles and groups take effect on all nodes")
+}
+
+// Annotate provides schema documentation for the clearUserCache function
+func (c *ClearUserCache) Annotate(a infer.Annotator) {
+	a.Describe(&c, "Clears the user cache, including users imported from user federation providers")
+}
+
+// Annotate provides schema documentation for the clearKeysCache function
+func (c *ClearKeysCache) Annotate(a infer.Annotator) {
+	a.Describe(&c, "Clears the cache of external public keys, such as the keys of identity providers and clients")
+}
+
+func (args *ClearCacheArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+}
+
+func (result *ClearCacheResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.Cache, "The cache that was cleared")
+}
+
+func (c *ClearRealmCache) Invoke(ctx context.Context, req infer.FunctionRequest[ClearCacheArgs]) (infer.FunctionResponse[ClearCacheResult], error) {
+	return clearCache(ctx, req.Input.RealmID, "realm")
+}
+
+func (c *ClearUserCache) Invoke(ctx context.Context, req infer.FunctionRequest[ClearCacheArgs]) (infer.FunctionResponse[ClearCacheResult], error) {
+	return clearCache(ctx, req.Input.RealmID, "user")
+}
+
+func (c *ClearKeysCache) Invoke(ctx context.Context, req infer.FunctionRequest[ClearCacheArgs]) (infer.FunctionResponse[ClearCacheResult], error) {
+	return clearCache(ctx, req.Input.RealmID, "keys")
+}
+
+func clearCache(ctx context.Context, realmName, cache string) (infer.FunctionResponse[ClearCacheResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[ClearCacheResult]{}, err
+	}
+
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		Post(adminRealmURL(ctx, realmName, "clear-"+cache+"-cache"))
+	if err := checkResponse(resp, err); err != nil {
+		return infer.FunctionResponse[ClearCacheResult]{}, fmt.Errorf("failed to clear %s cache of realm %s: %w", cache, realmName, err)
+	}
+
+	return infer.FunctionResponse[ClearCacheResult]{
+		Output: ClearCacheResult{Cache: cache},
+	}, nil
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/go-resty/resty/v2"
+)
+
+// KeycloakClient is the admin API as resources and functions use it. The provider implements it with gocloak,
+// see ProviderConfig.connect; a ProviderConfig built with a clientFactory swaps in another implementation,
+// e.g. a fake in tests
+type KeycloakClient interface {
+	AdminClient
+	RealmClient
+	ClientClient
+	UserClient
+	RoleClient
+	GroupClient
+}
+
+// clientFactory returns a client for the admin API and an access token for it
+type clientFactory func(ctx context.Context, config ProviderConfig) (KeycloakClient, string, error)
+
+// AdminClient sends requests to admin API endpoints that have no typed method
+type AdminClient interface {
+	GetRequestWithBearerAuth(ctx context.Context, token string) *resty.Request
+}
+
+// RealmClient manages realms and their realm-wide settings
+type RealmClient interface {
+	GetRealm(ctx context.Context, token, realm string) (*gocloak.RealmRepresentation, error)
+	GetRealms(ctx context.Context, token string) ([]*gocloak.RealmRepresentation, error)
+	CreateRealm(ctx context.Context, token string, realm gocloak.RealmRepresentation) (string, error)
+	UpdateRealm(ctx context.Context, token string, realm gocloak.RealmRepresentation) error
+	DeleteRealm(ctx context.Context, token, realm string) error
+	GetIdentityProvider(ctx context.Context, token, realm, alias string) (*gocloak.IdentityProviderRepresentation, error)
+	GetAuthenticationFlows(ctx context.Context, token, realm string) ([]*gocloak.AuthenticationFlowRepresentation, error)
+}
+
+// ClientClient manages clients, their secrets and authorization policies
+type ClientClient interface {
+	GetClient(ctx context.Context, token, realm, idOfClient string) (*gocloak.Client, error)
+	GetClients(ctx context.Context, token, realm string, params gocloak.GetClientsParams) ([]*gocloak.Client, error)
+	GetClientSecret(ctx context.Context, token, realm, idOfClient string) (*gocloak.CredentialRepresentation, error)
+	RegenerateClientSecret(ctx context.Context, token, realm, idOfClient string) (*gocloak.CredentialRepresentation, error)
+	GetClientServiceAccount(ctx context.Context, token, realm, idOfClient string) (*gocloak.User, error)
+	GetClientUserSessions(ctx context.Context, token, realm, idOfClient string, params ...gocloak.GetClientUserSessionsParams) ([]*gocloak.UserSessionRepresentation, error)
+	GetPolicy(ctx context.Context, token, realm, idOfClient, policyID string) (*gocloak.PolicyRepresentation, error)
+	GetAuthorizationPolicyAssociatedPolicies(ctx context.Context, token, realm, idOfClient, policyID string) ([]*gocloak.PolicyRepresentation, error)
+}
+
+// UserClient manages users, their credentials, sessions and federated identities
+type UserClient interface {
+	GetUsers(ctx context.Context, token, realm string, params gocloak.GetUsersParams) ([]*gocloak.User, error)
+	DeleteUser(ctx context.Context, token, realm, userID string) error
+	SetPassword(ctx context.Context, token, userID, realm, password string, temporary bool) error
+	SendVerifyEmail(ctx context.Context, token, userID, realm string, params ...gocloak.SendVerificationMailParams) error
+	ExecuteActionsEmail(ctx context.Context, token, realm string, params gocloak.ExecuteActionsEmail) error
+	RevokeUserConsents(ctx context.Context, accessToken, realm, userID, clientID string) error
+	LogoutAllSessions(ctx context.Context, accessToken, realm, userID string) error
+	GetUserSessions(ctx context.Context, token, realm, userID string) ([]*gocloak.UserSessionRepresentation, error)
+	GetUserGroups(ctx context.Context, token, realm, userID string, params gocloak.GetGroupsParams) ([]*gocloak.Group, error)
+	GetUserFederatedIdentities(ctx context.Context, token, realm, userID string) ([]*gocloak.FederatedIdentityRepresentation, error)
+	CreateUserFederatedIdentity(ctx context.Context, token, realm, userID, providerID string, federatedIdentityRep gocloak.FederatedIdentityRepresentation) error
+	DeleteUserFederatedIdentity(ctx context.Context, token, realm, userID, providerID string) error
+	GetCredentials(ctx context.Context, token, realm, userID string) ([]*gocloak.CredentialRepresentation, error)
+	DeleteCredentials(ctx context.Context, token, realm, userID, credentialID string) error
+	GetCompositeRealmRolesByUserID(ctx context.Context, token, realm, userID string) ([]*gocloak.Role, error)
+	GetCompositeClientRolesByUserID(ctx context.Context, token, realm, idOfClient, userID string) ([]*gocloak.Role, error)
+}
+
+// RoleClient reads realm and client roles
+type RoleClient interface {
+	GetRealmRole(ctx context.Context, token, realm, roleName string) (*gocloak.Role, error)
+	GetClientRole(ctx context.Context, token, realm, idOfClient, roleName string) (*gocloak.Role, error)
+	GetCompositeRolesByRoleID(ctx context.Context, token, realm, roleID string) ([]*gocloak.Role, error)
+}
+
+// GroupClient reads groups and their members
+type GroupClient interface {
+	GetGroupMembers(ctx context.Context, token, realm, groupID string, params gocloak.GetGroupsParams) ([]*gocloak.User, error)
+}
+
+var _ KeycloakClient = (*gocloak.GoCloak)(nil)
@@ -0,0 +1,216 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// ClientAuthentication manages how a confidential client authenticates at the token endpoint.
+// The client itself is not owned by this resource; only the authenticator settings are applied.
+type ClientAuthentication struct{}
+
+type ClientAuthenticationArgs struct {
+	RealmID             string  `pulumi:"realmId" provider:"replaceOnChanges"`
+	ClientID            string  `pulumi:"clientId" provider:"replaceOnChanges"`
+	AuthenticatorType   string  `pulumi:"authenticatorType"`
+	JwksUrl             *string `pulumi:"jwksUrl,optional"`
+	Certificate         *string `pulumi:"certificate,optional"`
+	SubjectDn           *string `pulumi:"subjectDn,optional"`
+	AllowRegexSubjectDn *bool   `pulumi:"allowRegexSubjectDn,optional"`
+	SigningAlgorithm    *string `pulumi:"signingAlgorithm,optional"`
+}
+
+type ClientAuthenticationState struct {
+	ClientAuthenticationArgs
+}
+
+// Client attributes holding the authenticator settings
+const (
+	clientUseJwksUrlAttribute        = "use.jwks.url"
+	clientJwksUrlAttribute           = "jwks.url"
+	clientCertificateAttribute       = "jwt.credential.certificate"
+	clientSubjectDnAttribute         = "x509.subjectdn"
+	clientAllowRegexAttribute        = "x509.allow.regex.pattern.comparison"
+	clientSigningAlgorithmAttribute  = "token.endpoint.auth.signing.alg"
+	clientAuthenticatorTypeClientJwt = "client-jwt"
+)
+
+// Annotate provides schema documentation for the ClientAuthentication resource
+func (c *ClientAuthentication) Annotate(a infer.Annotator) {
+	a.Describe(&c, "Client authenticator settings of an existing Keycloak client (client secret, signed JWT or X.509)")
+}
+
+func (args *ClientAuthenticationArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.ClientID, "The internal ID of the client")
+	a.Describe(&args.AuthenticatorType, "Client authenticator: client-secret, client-jwt (private_key_jwt), client-secret-jwt or client-x509")
+	a.Describe(&args.JwksUrl, "URL of the JWKS holding the client's public keys, used with client-jwt")
+	a.Describe(&args.Certificate, "PEM encoded certificate used to verify signed JWTs when no JWKS URL is set")
+	a.Describe(&args.SubjectDn, "Expected subject DN of the client certificate, used with client-x509")
+	a.Describe(&args.AllowRegexSubjectDn, "Whether subjectDn is matched as a regular expression")
+	a.Describe(&args.SigningAlgorithm, "Algorithm the client must use to sign JWTs for authentication, e.g. RS256")
+}
+
+func (c *ClientAuthentication) Create(ctx context.Context, req infer.CreateRequest[ClientAuthenticationArgs]) (infer.CreateResponse[ClientAuthenticationState], error) {
+	id := req.Inputs.RealmID + "/" + req.Inputs.ClientID
+
+	if req.DryRun {
+		return infer.CreateResponse[ClientAuthenticationState]{
+			ID:     id,
+			Output: ClientAuthenticationState{req.Inputs},
+		}, nil
+	}
+
+	state, err := applyClientAuthentication(ctx, req.Inputs)
+	if err != nil {
+		return infer.CreateResponse[ClientAuthenticationState]{}, err
+	}
+
+	return infer.CreateResponse[ClientAuthenticationState]{
+		ID:     id,
+		Output: state,
+	}, nil
+}
+
+func (c *ClientAuthentication) Update(ctx context.Context, req infer.UpdateRequest[ClientAuthenticationArgs, ClientAuthenticationState]) (infer.UpdateResponse[ClientAuthenticationState], error) {
+	if req.DryRun {
+		return infer.UpdateResponse[ClientAuthenticationState]{
+			Output: ClientAuthenticationState{req.Inputs},
+		}, nil
+	}
+
+	state, err := applyClientAuthentication(ctx, req.Inputs)
+	if err != nil {
+		return infer.UpdateResponse[ClientAuthenticationState]{}, err
+	}
+
+	return infer.UpdateResponse[ClientAuthenticationState]{
+		Output: state,
+	}, nil
+}
+
+// Delete leaves the authenticator settings in place, since they are part of the client itself
+func (c *ClientAuthentication) Delete(ctx context.Context, req infer.DeleteRequest[ClientAuthenticationState]) (infer.DeleteResponse, error) {
+	return infer.DeleteResponse{}, nil
+}
+
+func (c *ClientAuthentication) Read(ctx context.Context, req infer.ReadRequest[ClientAuthenticationArgs, ClientAuthenticationState]) (infer.ReadResponse[ClientAuthenticationArgs, ClientAuthenticationState], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.ReadResponse[ClientAuthenticationArgs, ClientAuthenticationState]{}, err
+	}
+
+	parts, err := parseResourceID(req.ID, "<realm>/<clientId>")
+	if err != nil {
+		return infer.ReadResponse[ClientAuthenticationArgs, ClientAuthenticationState]{}, err
+	}
+
+	state, err := readClientAuthenticationState(ctx, client, token, parts[0], parts[1])
+	if err != nil {
+		if isNotFound(err) {
+			return infer.ReadResponse[ClientAuthenticationArgs, ClientAuthenticationState]{}, nil
+		}
+		return infer.ReadResponse[ClientAuthenticationArgs, ClientAuthenticationState]{}, err
+	}
+
+	return infer.ReadResponse[ClientAuthenticationArgs, ClientAuthenticationState]{
+		ID:     req.ID,
+		Inputs: state.ClientAuthenticationArgs,
+		State:  state,
+	}, nil
+}
+
+// applyClientAuthentication writes the authenticator settings onto the client.
+// The client is handled as a raw map so fields gocloak does not model survive the round trip.
+func applyClientAuthentication(ctx context.Context, args ClientAuthenticationArgs) (ClientAuthenticationState, error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return ClientAuthenticationState{}, err
+	}
+
+	url := adminRealmURL(ctx, args.RealmID, "clients", args.ClientID)
+
+	var representation map[string]interface{}
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetResult(&representation).
+		Get(url)
+	if err := checkResponse(resp, err); err != nil {
+		return ClientAuthenticationState{}, fmt.Errorf("failed to get client: %w", err)
+	}
+
+	attributes, _ := representation["attributes"].(map[string]interface{})
+	if attributes == nil {
+		attributes = make(map[string]interface{})
+	}
+	if args.AuthenticatorType == clientAuthenticatorTypeClientJwt {
+		attributes[clientUseJwksUrlAttribute] = fmt.Sprintf("%t", args.JwksUrl != nil)
+	}
+	if args.JwksUrl != nil {
+		attributes[clientJwksUrlAttribute] = *args.JwksUrl
+	}
+	if args.Certificate != nil {
+		attributes[clientCertificateAttribute] = *args.Certificate
+	}
+	if args.SubjectDn != nil {
+		attributes[clientSubjectDnAttribute] = *args.SubjectDn
+	}
+	if args.AllowRegexSubjectDn != nil {
+		attributes[clientAllowRegexAttribute] = fmt.Sprintf("%t", *args.AllowRegexSubjectDn)
+	}
+	if args.SigningAlgorithm != nil {
+		attributes[clientSigningAlgorithmAttribute] = *args.SigningAlgorithm
+	}
+	representation["attributes"] = attributes
+	representation["clientAuthenticatorType"] = args.AuthenticatorType
+
+	resp, err = client.GetRequestWithBearerAuth(ctx, token).
+		SetBody(representation).
+		Put(url)
+	if err := checkResponse(resp, err); err != nil {
+		return ClientAuthenticationState{}, fmt.Errorf("failed to update client authentication: %w", err)
+	}
+
+	return readClientAuthenticationState(ctx, client, token, args.RealmID, args.ClientID)
+}
+
+func readClientAuthenticationState(ctx context.Context, client KeycloakClient, token, realmName, idOfClient string) (ClientAuthenticationState, error) {
+	var representation struct {
+		ClientAuthenticatorType string            `json:"clientAuthenticatorType"`
+		Attributes              map[string]string `json:"attributes"`
+	}
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetResult(&representation).
+		Get(adminRealmURL(ctx, realmName, "clients", idOfClient))
+	if err := checkResponse(resp, err); err != nil {
+		return ClientAuthenticationState{}, fmt.Errorf("failed to get client: %w", err)
+	}
+
+	state := ClientAuthenticationState{
+		ClientAuthenticationArgs{
+			RealmID:           realmName,
+			ClientID:          idOfClient,
+			AuthenticatorType: representation.ClientAuthenticatorType,
+		},
+	}
+
+	attribute := func(key string) *string {
+		if value, ok := representation.Attributes[key]; ok && value != "" {
+			return &value
+		}
+		return nil
+	}
+	if representation.Attributes[clientUseJwksUrlAttribute] != "false" {
+		state.JwksUrl = attribute(clientJwksUrlAttribute)
+	}
+	state.Certificate = attribute(clientCertificateAttribute)
+	state.SubjectDn = attribute(clientSubjectDnAttribute)
+	state.SigningAlgorithm = attribute(clientSigningAlgorithmAttribute)
+	if allowRegex := attribute(clientAllowRegexAttribute); allowRegex != nil {
+		allowRegexBool := *allowRegex == "true"
+		state.AllowRegexSubjectDn = &allowRegexBool
+	}
+
+	return state, nil
+}
@@ -2,76 +2,658 @@ package provider
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Nerzal/gocloak/v13"
+	"github.com/go-resty/resty/v2"
+	p "github.com/pulumi/pulumi-go-provider"
 	"github.com/pulumi/pulumi-go-provider/infer"
+	"golang.org/x/net/http/httpproxy"
 )
 
 // ProviderConfig holds the configuration for the Keycloak provider
 type ProviderConfig struct {
-	URL      string  `pulumi:"url"`               // Keycloak server URL (required)
-	Username string  `pulumi:"username"`          // Keycloak admin username (required)
-	Password string  `pulumi:"password"`          // Keycloak admin password (required)
-	Realm    *string `pulumi:"realm,optional"`    // Keycloak admin realm (optional, defaults to "master")
-	BasePath *string `pulumi:"basePath,optional"` // Base path for Keycloak (optional, defaults to "/")
-	Insecure *bool   `pulumi:"insecure,optional"` // Whether to use insecure connections (optional, defaults to false)
+	URL                        string            `pulumi:"url,optional"`                                 // Keycloak server URL (required, or KEYCLOAK_URL)
+	Username                   string            `pulumi:"username,optional"`                            // Keycloak admin username (required unless clientId is set)
+	Password                   string            `pulumi:"password,optional" provider:"secret"`          // Keycloak admin password (required unless clientId is set)
+	ClientID                   *string           `pulumi:"clientId,optional"`                            // Client used for the client credentials grant (optional)
+	ClientSecret               *string           `pulumi:"clientSecret,optional" provider:"secret"`      // Secret of the client used for the client credentials grant (optional)
+	Otp                        *string           `pulumi:"otp,optional" provider:"secret"`               // One-time password for admin accounts with MFA (optional)
+	TotpSecret                 *string           `pulumi:"totpSecret,optional" provider:"secret"`        // TOTP secret used to generate one-time passwords (optional)
+	PasswordFile               *string           `pulumi:"passwordFile,optional"`                        // File holding the admin password (optional)
+	ClientSecretFile           *string           `pulumi:"clientSecretFile,optional"`                    // File holding the client secret (optional)
+	AccessToken                *string           `pulumi:"accessToken,optional" provider:"secret"`       // Pre-acquired admin access token (optional)
+	RefreshToken               *string           `pulumi:"refreshToken,optional" provider:"secret"`      // Refresh token used to obtain a new access token (optional)
+	TokenRefreshMargin         *int              `pulumi:"tokenRefreshMargin,optional"`                  // Seconds before expiry the admin token is refreshed (optional, defaults to 30)
+	Realm                      *string           `pulumi:"realm,optional"`                               // Realm the provider authenticates against (optional, defaults to "master")
+	BasePath                   *string           `pulumi:"basePath,optional"`                            // Base path for Keycloak (optional, defaults to "/")
+	Legacy                     *bool             `pulumi:"legacy,optional"`                              // Whether the server is RH-SSO 7.x or Keycloak 16 and older (optional, defaults to false)
+	Insecure                   *bool             `pulumi:"insecure,optional"`                            // Whether to skip TLS verification (optional, defaults to false)
+	RootCaCertificate          *string           `pulumi:"rootCaCertificate,optional"`                   // PEM CA bundle trusted in addition to the system roots (optional)
+	ClientCertificate          *string           `pulumi:"clientCertificate,optional"`                   // PEM client certificate for mutual TLS (optional)
+	ClientKey                  *string           `pulumi:"clientKey,optional" provider:"secret"`         // PEM private key of the client certificate (optional)
+	MaxRetries                 *int              `pulumi:"maxRetries,optional"`                          // Retries of throttled or transiently failing requests (optional, defaults to 3)
+	MaxBackoff                 *int              `pulumi:"maxBackoff,optional"`                          // Maximum wait between retries in seconds (optional, defaults to 30)
+	MaxConcurrentRequests      *int              `pulumi:"maxConcurrentRequests,optional"`               // Limit on concurrent admin API requests (optional, unlimited by default)
+	MaxIdleConnections         *int              `pulumi:"maxIdleConnections,optional"`                  // Idle connections kept open to Keycloak for reuse (optional, defaults to 64)
+	IdleConnectionTimeout      *int              `pulumi:"idleConnectionTimeout,optional"`               // Seconds an idle connection is kept open (optional, defaults to 90)
+	RequestsPerSecond          *float64          `pulumi:"requestsPerSecond,optional"`                   // Limit on admin API requests started per second (optional, unlimited by default)
+	AdditionalHeaders          map[string]string `pulumi:"additionalHeaders,optional" provider:"secret"` // Headers sent with every request (optional)
+	OtlpEndpoint               *string           `pulumi:"otlpEndpoint,optional"`                        // OTLP/gRPC endpoint traces are exported to (optional)
+	Debug                      *bool             `pulumi:"debug,optional"`                               // Whether to log admin API traffic (optional, defaults to false)
+	ProxyURL                   *string           `pulumi:"proxyUrl,optional"`                            // HTTP, HTTPS or SOCKS5 proxy for requests to Keycloak (optional)
+	NoProxy                    *string           `pulumi:"noProxy,optional"`                             // Hosts reached without the proxy (optional)
+	ManagementMode             *string           `pulumi:"managementMode,optional"`                      // How resources treat unset fields, merge or full (optional, defaults to merge)
+	AllowMasterRealmManagement *bool             `pulumi:"allowMasterRealmManagement,optional"`          // Whether the master and admin realms may be deleted or disabled (optional, defaults to false)
+
+	clientFactory clientFactory    // Replaces the gocloak client, set on the config the provider is built with
+	client        *gocloak.GoCloak // Shared by all operations, created in Configure
+	realms        *realmCache      // Realms recently fetched with client
 }
 
 func (config *ProviderConfig) Annotate(a infer.Annotator) {
 	a.Describe(&config.URL, "Keycloak server URL (e.g., http://localhost:8080)")
 	a.Describe(&config.Username, "Keycloak admin username")
 	a.Describe(&config.Password, "Keycloak admin password")
-	a.Describe(&config.Realm, "Keycloak admin realm")
-	a.Describe(&config.BasePath, "Base path for Keycloak API")
-	a.Describe(&config.Insecure, "Whether to allow insecure connections")
+	a.Describe(&config.ClientID, "Client ID of a confidential client with a service account, used instead of username and password")
+	a.Describe(&config.ClientSecret, "Client secret of the service account client")
+	a.Describe(&config.Otp, "One-time password sent with the admin username and password when the admin account requires OTP. "+
+		"Only valid for a single login, so prefer totpSecret for anything but short runs")
+	a.Describe(&config.TotpSecret, "Base32 TOTP secret of the admin account, used to generate a one-time password for each login "+
+		"with Keycloak's default policy (HMAC-SHA1, 6 digits, 30 seconds)")
+	a.Describe(&config.PasswordFile, "Path of a file holding the admin password, e.g. a mounted Kubernetes secret. Read on every login when password is not set")
+	a.Describe(&config.ClientSecretFile, "Path of a file holding the client secret, e.g. a mounted Kubernetes secret. Read on every login when clientSecret is not set")
+	a.Describe(&config.AccessToken, "Access token obtained outside the provider, used as-is instead of logging in")
+	a.Describe(&config.RefreshToken, "Refresh token exchanged for a new access token when accessToken is not set or has expired. "+
+		"It is refreshed with clientId and clientSecret when set, otherwise with the admin-cli client")
+	a.Describe(&config.Realm, "Realm the provider authenticates against. Outside master, use a realm-scoped admin or service account "+
+		"with realm-management roles; it can manage that realm but not create realms")
+	a.Describe(&config.BasePath, "Path Keycloak is served under, e.g. /auth for Keycloak 16 and older or RH-SSO")
+	a.Describe(&config.Legacy, "Whether the server is RH-SSO 7.x or Keycloak 16 and older. Serves the API under /auth unless basePath is set "+
+		"to something other than /, and reports features of newer Keycloak releases as unavailable instead of probing the server")
+	a.Describe(&config.Insecure, "Whether to skip TLS certificate verification, e.g. for self-signed development instances")
+	a.Describe(&config.RootCaCertificate, "PEM encoded CA certificates trusted in addition to the system roots, e.g. a corporate CA")
+	a.Describe(&config.ClientCertificate, "PEM encoded client certificate presented for mutual TLS, together with clientKey")
+	a.Describe(&config.ClientKey, "PEM encoded private key of clientCertificate")
+	a.Describe(&config.TokenRefreshMargin, "How many seconds before it expires the admin token is renewed with its refresh token, "+
+		"in the background as well as on use, so that long deployments never send an expired token")
+	a.Describe(&config.MaxRetries, "How often a request is retried after a 429, 502, 503 or 504 response or a network error. 0 disables retries")
+	a.Describe(&config.MaxBackoff, "Maximum wait in seconds between retries, which otherwise back off exponentially with jitter or follow Retry-After")
+	a.Describe(&config.MaxConcurrentRequests, "Maximum number of admin API requests in flight at once across all resources, "+
+		"to keep large parallel updates from overwhelming a small Keycloak instance")
+	a.Describe(&config.MaxIdleConnections, "How many idle connections to Keycloak are kept open for reuse by later requests. "+
+		"Raise it for stacks updated with high parallelism, so that requests do not wait for new connections and TLS handshakes")
+	a.Describe(&config.IdleConnectionTimeout, "How many seconds an idle connection to Keycloak is kept open. 0 keeps it until the server closes it")
+	a.Describe(&config.RequestsPerSecond, "Maximum number of admin API requests started per second across all resources")
+	a.Describe(&config.AdditionalHeaders, "HTTP headers sent with every request to Keycloak, e.g. WAF bypass tokens or tenant headers")
+	a.Describe(&config.OtlpEndpoint, "OTLP/gRPC endpoint, e.g. http://localhost:4317, to export OpenTelemetry traces of resource operations "+
+		"and admin API calls to. Tracing is disabled when unset")
+	a.Describe(&config.Debug, "Whether to log admin API requests and responses as debug diagnostics, with tokens, passwords and secrets redacted. "+
+		"Shown with pulumi up --debug")
+	a.Describe(&config.ProxyURL, "Proxy for requests to Keycloak, e.g. http://proxy:3128 or socks5://proxy:1080. "+
+		"When unset, the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are honored")
+	a.Describe(&config.NoProxy, "Comma-separated hosts, domains and CIDRs reached without proxyUrl, in NO_PROXY syntax")
+	a.Describe(&config.ManagementMode, "Default managementMode of resources. merge leaves fields without an input as they are in Keycloak, "+
+		"preserving manual changes; full resets them to Keycloak's defaults so the program is the single source of truth")
+	a.Describe(&config.AllowMasterRealmManagement, "Whether the master realm and the realm the provider authenticates against may be "+
+		"deleted, replaced or disabled. Off by default, so that a pulumi destroy cannot lock administrators out of Keycloak")
 
-	a.SetDefault(&config.Realm, "master")
-	a.SetDefault(&config.BasePath, "/")
+	a.SetDefault(&config.URL, nil, "KEYCLOAK_URL")
+	a.SetDefault(&config.Username, nil, "KEYCLOAK_USERNAME")
+	a.SetDefault(&config.Password, nil, "KEYCLOAK_PASSWORD")
+	a.SetDefault(&config.PasswordFile, nil, "KEYCLOAK_PASSWORD_FILE")
+	a.SetDefault(&config.ClientSecretFile, nil, "KEYCLOAK_CLIENT_SECRET_FILE")
+	a.SetDefault(&config.Realm, "master", "KEYCLOAK_REALM")
+	a.SetDefault(&config.BasePath, "/", "KEYCLOAK_BASE_PATH")
+	a.SetDefault(&config.Legacy, false, "KEYCLOAK_LEGACY")
 	a.SetDefault(&config.Insecure, false)
+	a.SetDefault(&config.OtlpEndpoint, nil, "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "OTEL_EXPORTER_OTLP_ENDPOINT")
+	a.SetDefault(&config.Debug, false, "KEYCLOAK_DEBUG")
+	a.SetDefault(&config.TokenRefreshMargin, 30)
+	a.SetDefault(&config.MaxRetries, 3)
+	a.SetDefault(&config.MaxIdleConnections, defaultMaxIdleConnections)
+	a.SetDefault(&config.IdleConnectionTimeout, 90)
+	a.SetDefault(&config.MaxBackoff, 30)
+	a.SetDefault(&config.ManagementMode, managementModeMerge, "KEYCLOAK_MANAGEMENT_MODE")
+	a.SetDefault(&config.AllowMasterRealmManagement, false)
+}
+
+// Configure validates the management mode, sets up tracing, creates the client shared by all operations and detects
+// the server version once the configuration is known, so that resources needing newer Keycloak releases fail fast
+// with a clear error. Failures to detect the version are only logged here: during previews the credentials may not be
+// known yet, and the first operation reports the same problem with more context
+func (config *ProviderConfig) Configure(ctx context.Context) error {
+	if err := validateManagementMode(config.ManagementMode); err != nil {
+		return err
+	}
+
+	if config.OtlpEndpoint != nil {
+		if err := setupTracing(ctx, *config.OtlpEndpoint); err != nil {
+			return err
+		}
+	}
+
+	// One client keeps its connections alive across operations, instead of a TLS handshake for each resource
+	if config.clientFactory == nil {
+		client, err := config.newClient()
+		if err != nil {
+			return err
+		}
+		config.client = client
+		// Many resources of one realm read it during a deployment, see realmCache
+		config.realms = newRealmCache(realmCacheTTL)
+		config.realms.invalidateOnWrites(client)
+	}
+
+	client, token, err := config.connect(ctx)
+	if err != nil {
+		p.GetLogger(ctx).Debugf("cannot detect the Keycloak server version yet: %v", err)
+		return nil
+	}
+
+	version, err := detectServerVersion(ctx, *config, client, token)
+	if err != nil {
+		p.GetLogger(ctx).Debugf("cannot detect the Keycloak server version yet: %v", err)
+		return nil
+	}
+	p.GetLogger(ctx).Debugf("connected to Keycloak %s at %s", version, config.baseURL())
+
+	return nil
 }
 
-type KeycloakProvider struct {
-	Config *ProviderConfig
-	Client *gocloak.GoCloak
-	Token  *gocloak.JWT
+// diffConfig reports the blast radius of a configuration change. Pointing the provider at another server replaces
+// every resource it manages, since none of them exist there. Everything else, including rotated credentials, updates
+// the provider in place and leaves the resources alone
+func diffConfig(ctx context.Context, req p.DiffRequest) (p.DiffResponse, error) {
+	diff := map[string]p.PropertyDiff{}
+	for key, value := range req.Inputs.All {
+		if old, ok := req.State.GetOk(key); !ok {
+			diff[key] = p.PropertyDiff{Kind: p.Add, InputDiff: true}
+		} else if !value.Equals(old) {
+			diff[key] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+		}
+	}
+	for key := range req.State.All {
+		if _, ok := req.Inputs.GetOk(key); !ok {
+			diff[key] = p.PropertyDiff{Kind: p.Delete, InputDiff: true}
+		}
+	}
+
+	// An unknown url may still turn out to be the same, so only a known new server is flagged
+	olds, news := req.State.Get("url"), req.Inputs.Get("url")
+	if _, ok := diff["url"]; ok && olds.IsString() && news.IsString() {
+		if strings.TrimRight(olds.AsString(), "/") == strings.TrimRight(news.AsString(), "/") {
+			delete(diff, "url")
+		} else {
+			diff["url"] = p.PropertyDiff{Kind: p.UpdateReplace, InputDiff: true}
+			p.GetLogger(ctx).Warningf("changing the Keycloak url from %q to %q replaces every resource of this provider: they "+
+				"are created on the new server and deleted from the old one", olds.AsString(), news.AsString())
+		}
+	}
+
+	return p.DiffResponse{
+		HasChanges:   len(diff) > 0,
+		DetailedDiff: diff,
+	}, nil
 }
 
-func (p *KeycloakProvider) Configure(ctx context.Context, config ProviderConfig) error {
+// validateCredentials checks that a complete way of authenticating is configured. Tokens take precedence over
+// clientId, which takes precedence over username and password, so that KEYCLOAK_USERNAME and KEYCLOAK_PASSWORD
+// in the environment do not conflict with other credentials
+func (config ProviderConfig) validateCredentials() error {
 	if config.URL == "" {
-		return fmt.Errorf("keycloak URL is required")
+		return fmt.Errorf("keycloak url is required: set url or KEYCLOAK_URL")
+	}
+
+	if config.AccessToken != nil || config.RefreshToken != nil {
+		if config.RefreshToken == nil && config.ClientID != nil {
+			return fmt.Errorf("keycloak clientId cannot be combined with accessToken unless a refreshToken is set")
+		}
+		return nil
 	}
+
+	if config.ClientID != nil {
+		if config.ClientSecret == nil || *config.ClientSecret == "" {
+			return fmt.Errorf("keycloak clientSecret is required when clientId is set")
+		}
+		return nil
+	}
+
 	if config.Username == "" {
-		return fmt.Errorf("keycloak username is required")
+		return fmt.Errorf("keycloak username is required: set username or KEYCLOAK_USERNAME, or use clientId or accessToken")
 	}
 	if config.Password == "" {
-		return fmt.Errorf("keycloak password is required")
+		return fmt.Errorf("keycloak password is required: set password or KEYCLOAK_PASSWORD")
+	}
+	return nil
+}
+
+// reauthenticate obtains a new admin token after the server rejected the cached one
+func (config ProviderConfig) reauthenticate(ctx context.Context, client *gocloak.GoCloak, rejected string) (*gocloak.JWT, error) {
+	if withFiles, err := config.readCredentialFiles(); err == nil {
+		invalidateToken(withFiles, rejected)
+	}
+	return config.authenticate(ctx, client)
+}
+
+// authenticate returns an admin token for the configured credentials. Tokens are shared by all operations
+// of the provider process and refreshed shortly before they expire, see tokenCache
+func (config ProviderConfig) authenticate(ctx context.Context, client *gocloak.GoCloak) (*gocloak.JWT, error) {
+	config, err := config.readCredentialFiles()
+	if err != nil {
+		return nil, err
+	}
+	if err := config.validateCredentials(); err != nil {
+		return nil, err
+	}
+
+	return cachedToken(ctx, config, client)
+}
+
+// readCredentialFiles fills the password and client secret from their files when they are not set directly.
+// The files are read on every call so that rotated secrets are picked up
+func (config ProviderConfig) readCredentialFiles() (ProviderConfig, error) {
+	if config.Password == "" && config.PasswordFile != nil {
+		password, err := readCredentialFile(*config.PasswordFile)
+		if err != nil {
+			return config, fmt.Errorf("failed to read keycloak passwordFile: %w", err)
+		}
+		config.Password = password
+	}
+	if config.ClientSecret == nil && config.ClientSecretFile != nil {
+		clientSecret, err := readCredentialFile(*config.ClientSecretFile)
+		if err != nil {
+			return config, fmt.Errorf("failed to read keycloak clientSecretFile: %w", err)
+		}
+		config.ClientSecret = &clientSecret
+	}
+	return config, nil
+}
+
+// readCredentialFile returns the content of a secret file without the trailing newline editors and
+// kubectl create secret --from-file tend to leave
+func readCredentialFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// requestToken obtains a new admin token: a pre-acquired access token, unless it has expired and a refresh
+// token is available, the client credentials grant when a clientId is set, otherwise the password grant of
+// the admin-cli client
+func (config ProviderConfig) requestToken(ctx context.Context, client *gocloak.GoCloak) (*gocloak.JWT, error) {
+	if config.AccessToken != nil && (config.RefreshToken == nil || !tokenExpired(*config.AccessToken)) {
+		return &gocloak.JWT{AccessToken: *config.AccessToken}, nil
+	}
+	if config.RefreshToken != nil {
+		return config.refreshToken(ctx, client, *config.RefreshToken)
+	}
+	if config.ClientID != nil {
+		return client.LoginClient(ctx, *config.ClientID, *config.ClientSecret, config.adminRealm())
+	}
+
+	otp, err := config.oneTimePassword()
+	if err != nil {
+		return nil, err
+	}
+	if otp == nil {
+		return client.LoginAdmin(ctx, config.Username, config.Password, config.adminRealm())
+	}
+	return client.GetToken(ctx, config.adminRealm(), gocloak.TokenOptions{
+		ClientID:  gocloak.StringP("admin-cli"),
+		GrantType: gocloak.StringP("password"),
+		Username:  &config.Username,
+		Password:  &config.Password,
+		Totp:      otp,
+	})
+}
+
+// oneTimePassword returns the OTP to send with the admin password: generated from totpSecret when set,
+// otherwise the configured otp, or nil when the admin account does not use OTP
+func (config ProviderConfig) oneTimePassword() (*string, error) {
+	if config.TotpSecret != nil {
+		code, err := totpCode(*config.TotpSecret, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("keycloak totpSecret: %w", err)
+		}
+		return &code, nil
+	}
+	return config.Otp, nil
+}
+
+// refreshToken exchanges a refresh token for a new admin token, using the configured client or admin-cli
+func (config ProviderConfig) refreshToken(ctx context.Context, client *gocloak.GoCloak, refreshToken string) (*gocloak.JWT, error) {
+	clientID, clientSecret := "admin-cli", ""
+	if config.ClientID != nil {
+		clientID = *config.ClientID
+	}
+	if config.ClientSecret != nil {
+		clientSecret = *config.ClientSecret
+	}
+	return client.RefreshToken(ctx, refreshToken, clientID, clientSecret, config.adminRealm())
+}
+
+// adminRealm returns the realm the provider authenticates against
+func (config ProviderConfig) adminRealm() string {
+	if config.Realm != nil {
+		return *config.Realm
 	}
+	return "master"
+}
 
-	// Set defaults
-	if config.Realm == nil {
-		defaultRealm := "master"
-		config.Realm = &defaultRealm
+// checkRealmProtection refuses to delete, replace or disable the master realm or the realm the provider authenticates
+// against, unless allowMasterRealmManagement is set
+func (config ProviderConfig) checkRealmProtection(realmName, operation string) error {
+	if config.AllowMasterRealmManagement != nil && *config.AllowMasterRealmManagement {
+		return nil
+	}
+	if realmName == "master" || realmName == config.adminRealm() {
+		return fmt.Errorf("refusing to %s realm %q, which Keycloak is administered through: set allowMasterRealmManagement to allow it", operation, realmName)
 	}
-	if config.BasePath == nil {
-		defaultBasePath := "/"
-		config.BasePath = &defaultBasePath
+	return nil
+}
+
+// baseURL returns the server URL joined with the configured base path, without a trailing slash.
+// Legacy servers are served under /auth unless another base path is configured
+func (config ProviderConfig) baseURL() string {
+	basePath := ""
+	if config.BasePath != nil {
+		basePath = strings.Trim(*config.BasePath, "/")
 	}
-	if config.Insecure == nil {
-		defaultInsecure := false
-		config.Insecure = &defaultInsecure
+	if basePath == "" && config.isLegacy() {
+		basePath = "auth"
 	}
 
-	p.Config = &config
+	url := strings.TrimRight(config.URL, "/")
+	if basePath != "" {
+		url += "/" + basePath
+	}
+	return url
+}
+
+// isLegacy reports whether the provider talks to RH-SSO 7.x or Keycloak 16 and older
+func (config ProviderConfig) isLegacy() bool {
+	return config.Legacy != nil && *config.Legacy
+}
 
-	client := gocloak.NewClient(config.URL)
-	token, err := client.LoginAdmin(ctx, config.Username, config.Password, *config.Realm)
+// newClient creates a gocloak client for the configured server with the configured transport settings
+func (config ProviderConfig) newClient() (*gocloak.GoCloak, error) {
+	tlsConfig, err := config.tlsConfig()
 	if err != nil {
-		return fmt.Errorf("failed to authenticate with Keycloak: %w", err)
+		return nil, err
 	}
 
-	p.Client = client
-	p.Token = token
+	client := gocloak.NewClient(config.baseURL())
+	client.RestyClient().SetLogger(discardLogger{})
+	if tlsConfig != nil {
+		client.RestyClient().SetTLSClientConfig(tlsConfig)
+	}
+	if config.ProxyURL != nil {
+		if err := config.setProxy(client); err != nil {
+			return nil, err
+		}
+	}
+	config.setConnectionPool(client)
+	client.RestyClient().SetHeaders(config.AdditionalHeaders)
+	config.setRetries(client)
+	if config.Debug != nil && *config.Debug {
+		setDebugLogging(client.RestyClient())
+	}
+	if config.OtlpEndpoint != nil {
+		httpClient := client.RestyClient().GetClient()
+		httpClient.Transport = &tracingTransport{base: httpClient.Transport}
+	}
+	config.setLimits(client)
+
+	// Outermost, so that logging in again does not wait for a limiter slot held by the rejected request
+	httpClient := client.RestyClient().GetClient()
+	httpClient.Transport = &reauthTransport{
+		base: httpClient.Transport,
+		renew: func(ctx context.Context, rejected string) (string, error) {
+			token, err := config.reauthenticate(ctx, client, rejected)
+			if err != nil {
+				return "", err
+			}
+			return token.AccessToken, nil
+		},
+	}
+	return client, nil
+}
+
+// setLimits routes the requests of a client through the process-wide limiter for the configured limits
+func (config ProviderConfig) setLimits(client *gocloak.GoCloak) {
+	concurrency, perSecond := 0, 0.0
+	if config.MaxConcurrentRequests != nil {
+		concurrency = *config.MaxConcurrentRequests
+	}
+	if config.RequestsPerSecond != nil {
+		perSecond = *config.RequestsPerSecond
+	}
+
+	limiter := sharedLimiter(concurrency, perSecond)
+	if limiter == nil {
+		return
+	}
+
+	httpClient := client.RestyClient().GetClient()
+	httpClient.Transport = &limitedTransport{base: httpClient.Transport, limiter: limiter}
+}
+
+// discardLogger silences resty's own logging, which would otherwise print every retried request to stderr.
+// Failures are returned as errors and traffic can be inspected with the debug option
+type discardLogger struct{}
+
+func (discardLogger) Errorf(string, ...interface{}) {}
+func (discardLogger) Warnf(string, ...interface{})  {}
+func (discardLogger) Debugf(string, ...interface{}) {}
+
+// setRetries makes a client retry throttled and transiently failing requests with jittered exponential backoff
+func (config ProviderConfig) setRetries(client *gocloak.GoCloak) {
+	maxRetries, maxBackoff := 3, 30
+	if config.MaxRetries != nil {
+		maxRetries = *config.MaxRetries
+	}
+	if config.MaxBackoff != nil {
+		maxBackoff = *config.MaxBackoff
+	}
+	if maxRetries <= 0 {
+		return
+	}
+
+	client.RestyClient().
+		SetRetryCount(maxRetries).
+		SetRetryWaitTime(500 * time.Millisecond).
+		SetRetryMaxWaitTime(time.Duration(maxBackoff) * time.Second).
+		SetRetryAfter(retryAfter).
+		AddRetryHook(func(resp *resty.Response, err error) {
+			// the hook also runs after the last attempt, which is not retried
+			if resp == nil || resp.Request == nil || resp.Request.Attempt > maxRetries {
+				return
+			}
+			reason := resp.Status()
+			if err != nil {
+				reason = err.Error()
+			}
+			p.GetLogger(resp.Request.Context()).Infof("retrying Keycloak request %s %s after %s", resp.Request.Method, resp.Request.URL, reason)
+		}).
+		AddRetryCondition(func(resp *resty.Response, err error) bool {
+			if err != nil {
+				return true
+			}
+			switch resp.StatusCode() {
+			case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+				return true
+			}
+			return false
+		})
+}
+
+// retryAfter waits as long as a Retry-After header in seconds asks for, which resty caps at the maximum
+// backoff, and falls back to the default backoff otherwise
+func retryAfter(_ *resty.Client, resp *resty.Response) (time.Duration, error) {
+	if resp == nil {
+		return 0, nil
+	}
+	seconds, err := strconv.Atoi(resp.Header().Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0, nil
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// setProxy routes the requests of a client through the configured proxy, except for the noProxy hosts
+func (config ProviderConfig) setProxy(client *gocloak.GoCloak) error {
+	if _, err := url.Parse(*config.ProxyURL); err != nil {
+		return fmt.Errorf("invalid keycloak proxyUrl: %w", err)
+	}
+
+	transport, ok := client.RestyClient().GetClient().Transport.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("cannot configure a proxy on the HTTP transport")
+	}
 
+	proxyConfig := httpproxy.Config{
+		HTTPProxy:  *config.ProxyURL,
+		HTTPSProxy: *config.ProxyURL,
+	}
+	if config.NoProxy != nil {
+		proxyConfig.NoProxy = *config.NoProxy
+	}
+	proxy := proxyConfig.ProxyFunc()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}
 	return nil
 }
+
+// defaultMaxIdleConnections is enough for every request of a parallel update to find an open connection
+const defaultMaxIdleConnections = 64
+
+// setConnectionPool keeps enough idle connections open for the requests of parallel operations to reuse them.
+// resty keeps only GOMAXPROCS+1 per host, so most requests of a parallel update would dial and handshake anew
+func (config ProviderConfig) setConnectionPool(client *gocloak.GoCloak) {
+	transport, ok := client.RestyClient().GetClient().Transport.(*http.Transport)
+	if !ok {
+		return
+	}
+
+	maxIdle, idleTimeout := defaultMaxIdleConnections, 90
+	if config.MaxIdleConnections != nil {
+		maxIdle = *config.MaxIdleConnections
+	}
+	if config.IdleConnectionTimeout != nil {
+		idleTimeout = *config.IdleConnectionTimeout
+	}
+
+	// All requests go to one host, so the total limit only has to admit the per-host one
+	transport.MaxIdleConnsPerHost = maxIdle
+	transport.MaxIdleConns = maxIdle
+	transport.IdleConnTimeout = time.Duration(idleTimeout) * time.Second
+	transport.DisableKeepAlives = maxIdle <= 0
+}
+
+// tlsConfig builds the TLS settings for the configured CA bundle, client certificate and insecure flag,
+// or returns nil when the defaults apply
+func (config ProviderConfig) tlsConfig() (*tls.Config, error) {
+	insecure := config.Insecure != nil && *config.Insecure
+	if !insecure && config.RootCaCertificate == nil && config.ClientCertificate == nil && config.ClientKey == nil {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: insecure}
+
+	if config.RootCaCertificate != nil {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(*config.RootCaCertificate)) {
+			return nil, fmt.Errorf("keycloak rootCaCertificate does not contain a valid PEM certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.ClientCertificate != nil || config.ClientKey != nil {
+		if config.ClientCertificate == nil || config.ClientKey == nil {
+			return nil, fmt.Errorf("keycloak clientCertificate and clientKey must be set together")
+		}
+		certificate, err := tls.X509KeyPair([]byte(*config.ClientCertificate), []byte(*config.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid keycloak client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
+
+// login returns a client for the configured server along with an admin access token
+func login(ctx context.Context) (KeycloakClient, string, error) {
+	return infer.GetConfig[ProviderConfig](ctx).connect(ctx)
+}
+
+// connect returns a client from the injected clientFactory, or else the shared gocloak client for the configured
+// server. Tokens are cached, see tokenCache, so this only logs in when the cached token is about to expire
+func (config ProviderConfig) connect(ctx context.Context) (KeycloakClient, string, error) {
+	if config.clientFactory != nil {
+		return config.clientFactory(ctx, config)
+	}
+
+	client := config.client
+	if client == nil {
+		var err error
+		if client, err = config.newClient(); err != nil {
+			return nil, "", err
+		}
+	}
+
+	token, err := config.authenticate(ctx, client)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to authenticate: %w", err)
+	}
+
+	if config.realms != nil && client == config.client {
+		return realmCachingClient{KeycloakClient: client, cache: config.realms}, token.AccessToken, nil
+	}
+	return client, token.AccessToken, nil
+}
+
+// tokenRefreshMargin returns how long before expiry the admin token is refreshed
+func (config ProviderConfig) tokenRefreshMargin() time.Duration {
+	if config.TokenRefreshMargin == nil || *config.TokenRefreshMargin < 0 {
+		return tokenExpiryMargin
+	}
+	return time.Duration(*config.TokenRefreshMargin) * time.Second
+}
+
+// tokenExpired reports whether the exp claim of a JWT lies in the past. The signature is not verified,
+// and tokens that cannot be parsed are treated as opaque and never expired
+func tokenExpired(token string) bool {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return false
+	}
+	return time.Now().Unix() >= claims.Exp
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blang/semver"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/integration"
+	presource "github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/property"
+)
+
+func TestDiffConfigReplacesOnlyForAnotherServer(t *testing.T) {
+	server, err := integration.NewServer(context.Background(), "keycloak", semver.MustParse("1.0.0"),
+		integration.WithProvider(Provider()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	olds := map[string]property.Value{
+		"url":      property.New("https://keycloak.example.com"),
+		"username": property.New("admin"),
+		"password": property.New("admin"),
+	}
+	for name, tc := range map[string]struct {
+		change map[string]property.Value
+		remove string
+		want   map[string]p.DiffKind
+	}{
+		"unchanged":        {want: map[string]p.DiffKind{}},
+		"trailing slash":   {change: map[string]property.Value{"url": property.New("https://keycloak.example.com/")}, want: map[string]p.DiffKind{}},
+		"another server":   {change: map[string]property.Value{"url": property.New("https://other.example.com")}, want: map[string]p.DiffKind{"url": p.UpdateReplace}},
+		"unknown url":      {change: map[string]property.Value{"url": property.New(property.Computed)}, want: map[string]p.DiffKind{"url": p.Update}},
+		"rotated password": {change: map[string]property.Value{"password": property.New("rotated")}, want: map[string]p.DiffKind{"password": p.Update}},
+		"added realm":      {change: map[string]property.Value{"realm": property.New("acme")}, want: map[string]p.DiffKind{"realm": p.Add}},
+		"removed username": {remove: "username", want: map[string]p.DiffKind{"username": p.Delete}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			news := property.NewMap(olds)
+			for key, value := range tc.change {
+				news = news.Set(key, value)
+			}
+			if tc.remove != "" {
+				news = news.Delete(tc.remove)
+			}
+
+			diff, err := server.DiffConfig(p.DiffRequest{Urn: presource.NewURN("test", "keycloak", "", "pulumi:providers:keycloak", "default"), State: property.NewMap(olds), Inputs: news})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ensureEqual(t, "has changes", len(tc.want) > 0, diff.HasChanges)
+			ensureEqual(t, "changed keys", len(tc.want), len(diff.DetailedDiff))
+			for key, kind := range tc.want {
+				ensureEqual(t, key, kind, diff.DetailedDiff[key].Kind)
+			}
+		})
+	}
+}
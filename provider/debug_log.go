@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	p "github.com/pulumi/pulumi-go-provider"
+)
+
+// maxLoggedBody is the number of body bytes logged per request or response
+const maxLoggedBody = 4096
+
+const redacted = "REDACTED"
+
+// sensitiveKeySuffixes end the JSON and form keys whose values are never logged, e.g. password, clientSecret,
+// refresh_token, bindCredential, privateKey or totp
+var sensitiveKeySuffixes = []string{"password", "secret", "token", "credential", "privatekey", "private_key", "assertion", "otp"}
+
+// setDebugLogging logs the admin API requests and responses of a client as Pulumi debug diagnostics,
+// with tokens, passwords and secrets redacted
+func setDebugLogging(client *resty.Client) {
+	client.OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+		message := fmt.Sprintf("keycloak request: %s %s", req.Method, req.URL)
+		if len(req.QueryParam) > 0 {
+			message += "?" + redactValues(req.QueryParam).Encode()
+		}
+		if body := requestBody(req); body != "" {
+			message += "\n" + body
+		}
+		p.GetLogger(req.Context()).Debug(message)
+		return nil
+	})
+
+	client.OnAfterResponse(func(_ *resty.Client, resp *resty.Response) error {
+		message := fmt.Sprintf("keycloak response: %s %s: %s in %s", resp.Request.Method, resp.Request.URL, resp.Status(), resp.Time())
+		if body := redactBody(resp.Body()); body != "" {
+			message += "\n" + body
+		}
+		p.GetLogger(resp.Request.Context()).Debug(message)
+		return nil
+	})
+
+	client.OnError(func(req *resty.Request, err error) {
+		p.GetLogger(req.Context()).Debugf("keycloak request failed: %s %s: %v", req.Method, req.URL, err)
+	})
+}
+
+// requestBody renders the form or JSON body of a request with sensitive values redacted
+func requestBody(req *resty.Request) string {
+	if len(req.FormData) > 0 {
+		return redactValues(req.FormData).Encode()
+	}
+
+	switch body := req.Body.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return redactBody(body)
+	case string:
+		return redactBody([]byte(body))
+	default:
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Sprintf("<%T body>", body)
+		}
+		return redactBody(encoded)
+	}
+}
+
+// redactBody redacts sensitive values of a JSON or form encoded body and truncates it for logging
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var rendered string
+	var decoded interface{}
+	if err := json.Unmarshal(body, &decoded); err == nil {
+		encoded, _ := json.Marshal(redactJSON(decoded))
+		rendered = string(encoded)
+	} else if values, err := url.ParseQuery(string(body)); err == nil && strings.Contains(string(body), "=") {
+		rendered = redactValues(values).Encode()
+	} else {
+		rendered = string(body)
+	}
+
+	if len(rendered) > maxLoggedBody {
+		rendered = rendered[:maxLoggedBody] + fmt.Sprintf("... (%d bytes)", len(rendered))
+	}
+	return rendered
+}
+
+// redactJSON replaces the values of sensitive keys anywhere in a decoded JSON document
+func redactJSON(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range value {
+			if isSensitiveKey(key) {
+				value[key] = redacted
+				continue
+			}
+			value[key] = redactJSON(nested)
+		}
+		// credential representations carry the secret in a generic value field
+		if _, ok := value["type"]; ok {
+			if _, ok := value["value"]; ok {
+				value["value"] = redacted
+			}
+		}
+		return value
+	case []interface{}:
+		for i, nested := range value {
+			value[i] = redactJSON(nested)
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+func redactValues(values url.Values) url.Values {
+	result := url.Values{}
+	for key, value := range values {
+		if isSensitiveKey(key) {
+			result[key] = []string{redacted}
+			continue
+		}
+		result[key] = value
+	}
+	return result
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, suffix := range sensitiveKeySuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
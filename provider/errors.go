@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	p "github.com/pulumi/pulumi-go-provider"
+)
+
+// apiErrorHints explain the admin API statuses whose cause is usually outside the program
+var apiErrorHints = map[int]string{
+	401: "Keycloak rejected the provider's credentials: check username and password, clientId and clientSecret, or accessToken",
+	403: "the account the provider authenticates with lacks a role this operation needs: grant it realm-management roles " +
+		"such as manage-realm, manage-users or manage-clients, or an admin role of the master realm to create realms",
+	409: "an object with the same name or alias already exists: import it with pulumi import or choose another name",
+	500: "Keycloak failed to handle the request: its server log has the details",
+	502: "a proxy in front of Keycloak could not reach it",
+	503: "Keycloak is unavailable, e.g. still starting",
+	504: "a proxy in front of Keycloak timed out waiting for it",
+}
+
+// withErrorHints appends a hint to the errors of resource operations and invokes that failed with an admin API status
+// whose cause is usually missing permissions or an unhealthy server
+func withErrorHints(provider p.Provider) p.Provider {
+	create, read, update, del, invoke := provider.Create, provider.Read, provider.Update, provider.Delete, provider.Invoke
+
+	provider.Create = func(ctx context.Context, req p.CreateRequest) (p.CreateResponse, error) {
+		resp, err := create(ctx, req)
+		return resp, withErrorHint(err)
+	}
+	provider.Read = func(ctx context.Context, req p.ReadRequest) (p.ReadResponse, error) {
+		resp, err := read(ctx, req)
+		return resp, withErrorHint(err)
+	}
+	provider.Update = func(ctx context.Context, req p.UpdateRequest) (p.UpdateResponse, error) {
+		resp, err := update(ctx, req)
+		return resp, withErrorHint(err)
+	}
+	provider.Delete = func(ctx context.Context, req p.DeleteRequest) error {
+		return withErrorHint(del(ctx, req))
+	}
+	provider.Invoke = func(ctx context.Context, req p.InvokeRequest) (p.InvokeResponse, error) {
+		resp, err := invoke(ctx, req)
+		return resp, withErrorHint(err)
+	}
+	return provider
+}
+
+func withErrorHint(err error) error {
+	var apiErr *gocloak.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	hint, ok := apiErrorHints[apiErr.Code]
+	if !ok {
+		return err
+	}
+	return fmt.Errorf("%w (%s)", err, hint)
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// ExecuteActionsEmail sends an email asking a user to perform required actions
+type ExecuteActionsEmail struct{}
+
+type ExecuteActionsEmailArgs struct {
+	RealmID     string   `pulumi:"realmId"`
+	UserID      string   `pulumi:"userId"`
+	Actions     []string `pulumi:"actions"`
+	Lifespan    *int     `pulumi:"lifespan,optional"`
+	ClientID    *string  `pulumi:"clientId,optional"`
+	RedirectUri *string  `pulumi:"redirectUri,optional"`
+}
+
+// Annotate provides schema documentation for the executeActionsEmail function
+func (e *ExecuteActionsEmail) Annotate(a infer.Annotator) {
+	a.Describe(&e, "Sends an email with a link that lets a user perform the given required actions")
+}
+
+func (args *ExecuteActionsEmailArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.UserID, "The ID of the user")
+	a.Describe(&args.Actions, "Required actions, e.g. UPDATE_PASSWORD, CONFIGURE_TOTP, VERIFY_EMAIL or UPDATE_PROFILE")
+	a.Describe(&args.Lifespan, "Validity of the link in seconds. Defaults to the realm's admin action token lifespan")
+	a.Describe(&args.ClientID, "The clientId of the client the user is sent back to afterwards")
+	a.Describe(&args.RedirectUri, "Where the user is redirected afterwards. Requires clientId")
+}
+
+func (e *ExecuteActionsEmail) Invoke(ctx context.Context, req infer.FunctionRequest[ExecuteActionsEmailArgs]) (infer.FunctionResponse[UserActionResult], error) {
+	if len(req.Input.Actions) == 0 {
+		return infer.FunctionResponse[UserActionResult]{}, fmt.Errorf("at least one action must be set")
+	}
+	if req.Input.RedirectUri != nil && req.Input.ClientID == nil {
+		return infer.FunctionResponse[UserActionResult]{}, fmt.Errorf("redirectUri requires clientId")
+	}
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[UserActionResult]{}, err
+	}
+
+	err = client.ExecuteActionsEmail(ctx, token, req.Input.RealmID, gocloak.ExecuteActionsEmail{
+		UserID:      gocloak.StringP(req.Input.UserID),
+		Actions:     &req.Input.Actions,
+		Lifespan:    req.Input.Lifespan,
+		ClientID:    req.Input.ClientID,
+		RedirectURI: req.Input.RedirectUri,
+	})
+	if err != nil {
+		return infer.FunctionResponse[UserActionResult]{}, fmt.Errorf("failed to send actions email to user %s: %w", req.Input.UserID, err)
+	}
+
+	return infer.FunctionResponse[UserActionResult]{
+		Output: UserActionResult{UserID: req.Input.UserID},
+	}, nil
+}
@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// ExportRealm returns a partial export of a realm as JSON
+type ExportRealm struct{}
+
+type ExportRealmArgs struct {
+	RealmID              string `pulumi:"realmId"`
+	ExportClients        *bool  `pulumi:"exportClients,optional"`
+	ExportGroupsAndRoles *bool  `pulumi:"exportGroupsAndRoles,optional"`
+}
+
+type ExportRealmResult struct {
+	Json string `pulumi:"json"`
+}
+
+// Annotate provides schema documentation for the exportRealm function
+func (e *ExportRealm) Annotate(a infer.Annotator) {
+	a.Describe(&e, "Returns a partial export of a realm as JSON. Users are not included and secrets are masked by Keycloak")
+}
+
+func (args *ExportRealmArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.ExportClients, "Whether to include clients")
+	a.Describe(&args.ExportGroupsAndRoles, "Whether to include groups and roles")
+
+	a.SetDefault(&args.ExportClients, false)
+	a.SetDefault(&args.ExportGroupsAndRoles, false)
+}
+
+func (result *ExportRealmResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.Json, "The realm representation as JSON")
+}
+
+func (e *ExportRealm) Invoke(ctx context.Context, req infer.FunctionRequest[ExportRealmArgs]) (infer.FunctionResponse[ExportRealmResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[ExportRealmResult]{}, err
+	}
+
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetQueryParams(map[string]string{
+			"exportClients":        strconv.FormatBool(gocloak.PBool(req.Input.ExportClients)),
+			"exportGroupsAndRoles": strconv.FormatBool(gocloak.PBool(req.Input.ExportGroupsAndRoles)),
+		}).
+		Post(adminRealmURL(ctx, req.Input.RealmID, "partial-export"))
+	if err := checkResponse(resp, err); err != nil {
+		return infer.FunctionResponse[ExportRealmResult]{}, fmt.Errorf("failed to export realm %s: %w", req.Input.RealmID, err)
+	}
+
+	return infer.FunctionResponse[ExportRealmResult]{
+		Output: ExportRealmResult{Json: resp.String()},
+	}, nil
+}
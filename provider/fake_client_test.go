@@ -0,0 +1,224 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/blang/semver"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/integration"
+	presource "github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/property"
+)
+
+// fakeKeycloak is an in-memory KeycloakClient that keeps realms. Calls it does not implement go to an unreachable
+// server and fail
+type fakeKeycloak struct {
+	*gocloak.GoCloak
+
+	mu     sync.Mutex
+	realms map[string][]byte
+	calls  map[string]int
+}
+
+var _ KeycloakClient = (*fakeKeycloak)(nil)
+
+func newFakeKeycloak() *fakeKeycloak {
+	return &fakeKeycloak{
+		GoCloak: gocloak.NewClient("http://127.0.0.1:1"),
+		realms:  map[string][]byte{},
+		calls:   map[string]int{},
+	}
+}
+
+func (f *fakeKeycloak) GetRealm(_ context.Context, _, realm string) (*gocloak.RealmRepresentation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls["GetRealm"]++
+
+	stored, ok := f.realms[realm]
+	if !ok {
+		return nil, &gocloak.APIError{Code: http.StatusNotFound, Message: "404 Not Found: Realm not found."}
+	}
+	var representation gocloak.RealmRepresentation
+	if err := json.Unmarshal(stored, &representation); err != nil {
+		return nil, err
+	}
+	if smtp := representation.SMTPServer; smtp != nil && (*smtp)["password"] != "" {
+		(*smtp)["password"] = maskedSmtpPassword
+	}
+	return &representation, nil
+}
+
+func (f *fakeKeycloak) CreateRealm(_ context.Context, _ string, realm gocloak.RealmRepresentation) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls["CreateRealm"]++
+
+	name := gocloak.PString(realm.Realm)
+	if _, ok := f.realms[name]; ok {
+		return "", &gocloak.APIError{Code: http.StatusConflict, Message: "409 Conflict: Conflict detected."}
+	}
+	realm.ID = gocloak.StringP(name)
+	return name, f.store(name, realm)
+}
+
+func (f *fakeKeycloak) UpdateRealm(_ context.Context, _ string, realm gocloak.RealmRepresentation) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls["UpdateRealm"]++
+
+	name := gocloak.PString(realm.Realm)
+	stored, ok := f.realms[name]
+	if !ok {
+		return &gocloak.APIError{Code: http.StatusNotFound, Message: "404 Not Found: Realm not found."}
+	}
+	// Keycloak keeps the SMTP password when it is sent back masked
+	if smtp := realm.SMTPServer; smtp != nil && (*smtp)["password"] == maskedSmtpPassword {
+		var previous gocloak.RealmRepresentation
+		if err := json.Unmarshal(stored, &previous); err != nil {
+			return err
+		}
+		updated := maps.Clone(*smtp)
+		updated["password"] = (*previous.SMTPServer)["password"]
+		realm.SMTPServer = &updated
+	}
+	return f.store(name, realm)
+}
+
+func (f *fakeKeycloak) DeleteRealm(_ context.Context, _, realm string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls["DeleteRealm"]++
+
+	if _, ok := f.realms[realm]; !ok {
+		return &gocloak.APIError{Code: http.StatusNotFound, Message: "404 Not Found: Realm not found."}
+	}
+	delete(f.realms, realm)
+	return nil
+}
+
+// store keeps a copy of a realm, as Keycloak would, so that callers cannot change it afterwards
+func (f *fakeKeycloak) store(name string, realm gocloak.RealmRepresentation) error {
+	encoded, err := json.Marshal(realm)
+	if err != nil {
+		return err
+	}
+	f.realms[name] = encoded
+	return nil
+}
+
+func (f *fakeKeycloak) callCount(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[method]
+}
+
+// newFakeServer runs the provider against a fake admin API, injected through the client factory. Each login
+// counts as a call to "login"
+func newFakeServer(t *testing.T, fake *fakeKeycloak) integration.Server {
+	t.Helper()
+
+	provider := newProvider(&ProviderConfig{
+		clientFactory: func(context.Context, ProviderConfig) (KeycloakClient, string, error) {
+			fake.mu.Lock()
+			defer fake.mu.Unlock()
+			fake.calls["login"]++
+			return fake, "fake-token", nil
+		},
+	})
+	server, err := integration.NewServer(context.Background(), "keycloak", semver.MustParse("1.0.0"),
+		integration.WithProvider(provider))
+	if err != nil {
+		t.Fatal(err)
+	}
+	configure(t, server, map[string]property.Value{})
+	return server
+}
+
+// newAdminAPIServer runs the provider with its gocloak client against an httptest server, for resources that send
+// raw admin API requests. The test registers the endpoints it needs on mux, which also serves logins and the server
+// version
+func newAdminAPIServer(t *testing.T, mux *http.ServeMux) integration.Server {
+	t.Helper()
+
+	mux.HandleFunc("POST /realms/master/protocol/openid-connect/token", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"access_token": "api-token", "expires_in": 300, "token_type": "Bearer"})
+	})
+	mux.HandleFunc("GET /admin/serverinfo", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"systemInfo": map[string]any{"version": "26.0.0"}})
+	})
+	api := httptest.NewServer(mux)
+	t.Cleanup(api.Close)
+
+	server, err := integration.NewServer(context.Background(), "keycloak", semver.MustParse("1.0.0"),
+		integration.WithProvider(Provider()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	configure(t, server, map[string]property.Value{"url": property.New(api.URL)})
+	return server
+}
+
+func configure(t *testing.T, server integration.Server, config map[string]property.Value) {
+	t.Helper()
+
+	args := map[string]property.Value{
+		"url":      property.New("http://keycloak.test"),
+		"username": property.New("admin"),
+		"password": property.New("admin"),
+	}
+	for key, value := range config {
+		args[key] = value
+	}
+	if err := server.Configure(p.ConfigureRequest{Args: property.NewMap(args)}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testURN(typ, name string) presource.URN {
+	return presource.NewURN("test", "keycloak", "", tokens.Type("keycloak:index:"+typ), name)
+}
+
+func realmInputs(name string, fields map[string]property.Value) property.Map {
+	inputs := map[string]property.Value{"name": property.New(name)}
+	for key, value := range fields {
+		inputs[key] = value
+	}
+	return property.NewMap(inputs)
+}
+
+func createRealm(t *testing.T, server integration.Server, inputs property.Map) p.CreateResponse {
+	t.Helper()
+
+	name := inputs.Get("name").AsString()
+	resp, err := server.Create(p.CreateRequest{Urn: testURN("Realm", name), Properties: inputs})
+	if err != nil {
+		t.Fatalf("create realm %s: %v", name, err)
+	}
+	return resp
+}
+
+func stringProperty(t *testing.T, values property.Map, key string) string {
+	t.Helper()
+
+	value := values.Get(key)
+	if !value.IsString() {
+		t.Fatalf("%s is %v, not a string", key, value)
+	}
+	return value.AsString()
+}
+
+func ensureEqual[T comparable](t *testing.T, what string, want, got T) {
+	t.Helper()
+	if want != got {
+		t.Errorf("%s: want %v, got %v", what, want, got)
+	}
+}
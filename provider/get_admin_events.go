@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// GetAdminEvents queries the admin events of a realm
+type GetAdminEvents struct{}
+
+type GetAdminEventsArgs struct {
+	RealmID        string   `pulumi:"realmId"`
+	OperationTypes []string `pulumi:"operationTypes,optional"`
+	ResourceTypes  []string `pulumi:"resourceTypes,optional"`
+	ResourcePath   *string  `pulumi:"resourcePath,optional"`
+	AuthUserID     *string  `pulumi:"authUserId,optional"`
+	AuthClientID   *string  `pulumi:"authClientId,optional"`
+	AuthIpAddress  *string  `pulumi:"authIpAddress,optional"`
+	DateFrom       *string  `pulumi:"dateFrom,optional"`
+	DateTo         *string  `pulumi:"dateTo,optional"`
+	Offset         int      `pulumi:"offset,optional"`
+	Limit          *int     `pulumi:"limit,optional"`
+	PageSize       *int     `pulumi:"pageSize,optional"`
+}
+
+type GetAdminEventsResult struct {
+	Events []AdminEvent `pulumi:"events"`
+}
+
+// AdminEvent describes a change made through the admin API
+type AdminEvent struct {
+	Time           string  `pulumi:"time"`
+	OperationType  string  `pulumi:"operationType"`
+	ResourceType   string  `pulumi:"resourceType"`
+	ResourcePath   string  `pulumi:"resourcePath"`
+	AuthRealmID    string  `pulumi:"authRealmId"`
+	AuthClientID   string  `pulumi:"authClientId"`
+	AuthUserID     string  `pulumi:"authUserId"`
+	AuthIpAddress  string  `pulumi:"authIpAddress"`
+	Representation *string `pulumi:"representation,optional"`
+	Error          *string `pulumi:"error,optional"`
+}
+
+// adminEventRepresentation is the admin API wire format of an admin event
+type adminEventRepresentation struct {
+	Time        int64 `json:"time"`
+	AuthDetails struct {
+		RealmID   string `json:"realmId"`
+		ClientID  string `json:"clientId"`
+		UserID    string `json:"userId"`
+		IpAddress string `json:"ipAddress"`
+	} `json:"authDetails"`
+	OperationType  string `json:"operationType"`
+	ResourceType   string `json:"resourceType"`
+	ResourcePath   string `json:"resourcePath"`
+	Representation string `json:"representation"`
+	Error          string `json:"error"`
+}
+
+// Annotate provides schema documentation for the getAdminEvents function
+func (g *GetAdminEvents) Annotate(a infer.Annotator) {
+	a.Describe(&g, "Queries the admin events of a realm, newest first. Admin events must be enabled in the realm's event settings")
+}
+
+func (args *GetAdminEventsArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.OperationTypes, "Only return these operations: CREATE, UPDATE, DELETE or ACTION")
+	a.Describe(&args.ResourceTypes, "Only return events for these resource types, e.g. USER, CLIENT or REALM_ROLE_MAPPING")
+	a.Describe(&args.ResourcePath, "Only return events for this resource path. * can be used as a wildcard")
+	a.Describe(&args.AuthUserID, "Only return events caused by this user ID")
+	a.Describe(&args.AuthClientID, "Only return events caused through this client ID")
+	a.Describe(&args.AuthIpAddress, "Only return events caused from this IP address")
+	a.Describe(&args.DateFrom, "Only return events on or after this date, in yyyy-MM-dd format")
+	a.Describe(&args.DateTo, "Only return events on or before this date, in yyyy-MM-dd format")
+	a.Describe(&args.Offset, "Number of matching events to skip")
+	a.Describe(&args.Limit, "Maximum number of events to return, at most 10000")
+	a.Describe(&args.PageSize, "Number of events fetched per admin API request, at most 1000. "+
+		"Larger pages need fewer requests, smaller ones put less load on Keycloak per request")
+
+	a.SetDefault(&args.Limit, 100)
+	a.SetDefault(&args.PageSize, pageSize)
+}
+
+func (result *GetAdminEventsResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.Events, "The matching admin events")
+}
+
+func (e *AdminEvent) Annotate(a infer.Annotator) {
+	a.Describe(&e.Time, "When the event happened, in RFC 3339 format")
+	a.Describe(&e.OperationType, "The operation: CREATE, UPDATE, DELETE or ACTION")
+	a.Describe(&e.ResourceType, "The type of the changed resource")
+	a.Describe(&e.ResourcePath, "The admin API path of the changed resource")
+	a.Describe(&e.AuthRealmID, "The realm of the admin who made the change")
+	a.Describe(&e.AuthClientID, "The client the admin used")
+	a.Describe(&e.AuthUserID, "The ID of the admin who made the change")
+	a.Describe(&e.AuthIpAddress, "The IP address of the admin")
+	a.Describe(&e.Representation, "The submitted representation, when the realm stores it")
+	a.Describe(&e.Error, "The error, when the operation failed")
+}
+
+func (g *GetAdminEvents) Invoke(ctx context.Context, req infer.FunctionRequest[GetAdminEventsArgs]) (infer.FunctionResponse[GetAdminEventsResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[GetAdminEventsResult]{}, err
+	}
+
+	query := url.Values{}
+	for _, operationType := range req.Input.OperationTypes {
+		query.Add("operationTypes", operationType)
+	}
+	for _, resourceType := range req.Input.ResourceTypes {
+		query.Add("resourceTypes", resourceType)
+	}
+	for key, value := range map[string]*string{
+		"resourcePath":  req.Input.ResourcePath,
+		"authUser":      req.Input.AuthUserID,
+		"authClient":    req.Input.AuthClientID,
+		"authIpAddress": req.Input.AuthIpAddress,
+		"dateFrom":      req.Input.DateFrom,
+		"dateTo":        req.Input.DateTo,
+	} {
+		if value != nil {
+			query.Set(key, *value)
+		}
+	}
+
+	result := GetAdminEventsResult{Events: []AdminEvent{}}
+	err = paginateListing(req.Input.Offset, req.Input.Limit, req.Input.PageSize, func(first, max int) (int, error) {
+		query.Set("first", strconv.Itoa(first))
+		query.Set("max", strconv.Itoa(max))
+
+		var events []adminEventRepresentation
+		resp, err := client.GetRequestWithBearerAuth(ctx, token).
+			SetQueryParamsFromValues(query).
+			SetResult(&events).
+			Get(adminRealmURL(ctx, req.Input.RealmID, "admin-events"))
+		if err := checkResponse(resp, err); err != nil {
+			return 0, fmt.Errorf("failed to get admin events: %w", err)
+		}
+
+		for _, event := range events {
+			result.Events = append(result.Events, AdminEvent{
+				Time:           formatMillis(&event.Time),
+				OperationType:  event.OperationType,
+				ResourceType:   event.ResourceType,
+				ResourcePath:   event.ResourcePath,
+				AuthRealmID:    event.AuthDetails.RealmID,
+				AuthClientID:   event.AuthDetails.ClientID,
+				AuthUserID:     event.AuthDetails.UserID,
+				AuthIpAddress:  event.AuthDetails.IpAddress,
+				Representation: optionalString(event.Representation),
+				Error:          optionalString(event.Error),
+			})
+		}
+		return len(events), nil
+	})
+	if err != nil {
+		return infer.FunctionResponse[GetAdminEventsResult]{}, err
+	}
+
+	return infer.FunctionResponse[GetAdminEventsResult]{
+		Output: result,
+	}, nil
+}
+
+// optionalString maps an empty string to nil
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// GetAuthenticationFlow looks up a top level authentication flow by alias
+type GetAuthenticationFlow struct{}
+
+type GetAuthenticationFlowArgs struct {
+	RealmID string `pulumi:"realmId"`
+	Alias   string `pulumi:"alias"`
+}
+
+type GetAuthenticationFlowResult struct {
+	FlowID      string  `pulumi:"flowId"`
+	Alias       string  `pulumi:"alias"`
+	Description *string `pulumi:"description,optional"`
+	ProviderID  *string `pulumi:"providerId,optional"`
+	BuiltIn     bool    `pulumi:"builtIn"`
+	TopLevel    bool    `pulumi:"topLevel"`
+}
+
+// Annotate provides schema documentation for the getAuthenticationFlow function
+func (g *GetAuthenticationFlow) Annotate(a infer.Annotator) {
+	a.Describe(&g, "Looks up an authentication flow by alias")
+}
+
+func (args *GetAuthenticationFlowArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.Alias, "The alias of the flow, e.g. browser")
+}
+
+func (result *GetAuthenticationFlowResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.FlowID, "The ID of the flow")
+	a.Describe(&result.Alias, "The alias of the flow")
+	a.Describe(&result.Description, "The description of the flow")
+	a.Describe(&result.ProviderID, "The flow provider, basic-flow or form-flow")
+	a.Describe(&result.BuiltIn, "Whether the flow is built into Keycloak")
+	a.Describe(&result.TopLevel, "Whether the flow is a top level flow")
+}
+
+func (g *GetAuthenticationFlow) Invoke(ctx context.Context, req infer.FunctionRequest[GetAuthenticationFlowArgs]) (infer.FunctionResponse[GetAuthenticationFlowResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[GetAuthenticationFlowResult]{}, err
+	}
+
+	flows, err := client.GetAuthenticationFlows(ctx, token, req.Input.RealmID)
+	if err != nil {
+		return infer.FunctionResponse[GetAuthenticationFlowResult]{}, fmt.Errorf("failed to get authentication flows: %w", err)
+	}
+
+	for _, flow := range flows {
+		if gocloak.PString(flow.Alias) != req.Input.Alias {
+			continue
+		}
+		return infer.FunctionResponse[GetAuthenticationFlowResult]{
+			Output: GetAuthenticationFlowResult{
+				FlowID:      gocloak.PString(flow.ID),
+				Alias:       gocloak.PString(flow.Alias),
+				Description: flow.Description,
+				ProviderID:  flow.ProviderID,
+				BuiltIn:     gocloak.PBool(flow.BuiltIn),
+				TopLevel:    gocloak.PBool(flow.TopLevel),
+			},
+		}, nil
+	}
+
+	return infer.FunctionResponse[GetAuthenticationFlowResult]{}, fmt.Errorf("authentication flow %s not found in realm %s", req.Input.Alias, req.Input.RealmID)
+}
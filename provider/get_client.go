@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// GetClient looks up an existing client by its clientId
+type GetClient struct{}
+
+type GetClientArgs struct {
+	RealmID       string `pulumi:"realmId"`
+	ClientID      string `pulumi:"clientId"`
+	IncludeSecret *bool  `pulumi:"includeSecret,optional"`
+}
+
+type GetClientResult struct {
+	UUID                      string            `pulumi:"uuid"`
+	ClientID                  string            `pulumi:"clientId"`
+	Name                      *string           `pulumi:"name,optional"`
+	Description               *string           `pulumi:"description,optional"`
+	Enabled                   *bool             `pulumi:"enabled,optional"`
+	Protocol                  *string           `pulumi:"protocol,optional"`
+	PublicClient              *bool             `pulumi:"publicClient,optional"`
+	BearerOnly                *bool             `pulumi:"bearerOnly,optional"`
+	ServiceAccountsEnabled    *bool             `pulumi:"serviceAccountsEnabled,optional"`
+	StandardFlowEnabled       *bool             `pulumi:"standardFlowEnabled,optional"`
+	DirectAccessGrantsEnabled *bool             `pulumi:"directAccessGrantsEnabled,optional"`
+	RootUrl                   *string           `pulumi:"rootUrl,optional"`
+	BaseUrl                   *string           `pulumi:"baseUrl,optional"`
+	RedirectUris              []string          `pulumi:"redirectUris,optional"`
+	WebOrigins                []string          `pulumi:"webOrigins,optional"`
+	ClientAuthenticatorType   *string           `pulumi:"clientAuthenticatorType,optional"`
+	Attributes                map[string]string `pulumi:"attributes,optional"`
+	Secret                    *string           `pulumi:"secret,optional" provider:"secret"`
+}
+
+// Annotate provides schema documentation for the getClient function
+func (g *GetClient) Annotate(a infer.Annotator) {
+	a.Describe(&g, "Looks up an existing client by its clientId")
+}
+
+func (args *GetClientArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.ClientID, "The clientId of the client, as used in OAuth requests")
+	a.Describe(&args.IncludeSecret, "Whether to return the client secret of a confidential client")
+}
+
+func (result *GetClientResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.UUID, "The internal ID (UUID) of the client")
+	a.Describe(&result.Secret, "The client secret, only set when includeSecret is true")
+}
+
+func (g *GetClient) Invoke(ctx context.Context, req infer.FunctionRequest[GetClientArgs]) (infer.FunctionResponse[GetClientResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[GetClientResult]{}, err
+	}
+
+	idOfClient, err := clientUUID(ctx, client, token, req.Input.RealmID, req.Input.ClientID)
+	if err != nil {
+		return infer.FunctionResponse[GetClientResult]{}, err
+	}
+
+	found, err := client.GetClient(ctx, token, req.Input.RealmID, idOfClient)
+	if err != nil {
+		return infer.FunctionResponse[GetClientResult]{}, fmt.Errorf("failed to get client %s: %w", req.Input.ClientID, err)
+	}
+
+	result := GetClientResult{
+		UUID:                      idOfClient,
+		ClientID:                  req.Input.ClientID,
+		Name:                      found.Name,
+		Description:               found.Description,
+		Enabled:                   found.Enabled,
+		Protocol:                  found.Protocol,
+		PublicClient:              found.PublicClient,
+		BearerOnly:                found.BearerOnly,
+		ServiceAccountsEnabled:    found.ServiceAccountsEnabled,
+		StandardFlowEnabled:       found.StandardFlowEnabled,
+		DirectAccessGrantsEnabled: found.DirectAccessGrantsEnabled,
+		RootUrl:                   found.RootURL,
+		BaseUrl:                   found.BaseURL,
+		ClientAuthenticatorType:   found.ClientAuthenticatorType,
+	}
+	if found.RedirectURIs != nil {
+		result.RedirectUris = *found.RedirectURIs
+	}
+	if found.WebOrigins != nil {
+		result.WebOrigins = *found.WebOrigins
+	}
+	if found.Attributes != nil {
+		result.Attributes = *found.Attributes
+	}
+
+	if gocloak.PBool(req.Input.IncludeSecret) && !gocloak.PBool(found.PublicClient) && !gocloak.PBool(found.BearerOnly) {
+		credential, err := client.GetClientSecret(ctx, token, req.Input.RealmID, idOfClient)
+		if err != nil {
+			return infer.FunctionResponse[GetClientResult]{}, fmt.Errorf("failed to get secret of client %s: %w", req.Input.ClientID, err)
+		}
+		result.Secret = credential.Value
+	}
+
+	return infer.FunctionResponse[GetClientResult]{
+		Output: result,
+	}, nil
+}
+
+// clientUUID resolves the internal ID of a client from its clientId
+func clientUUID(ctx context.Context, client KeycloakClient, token, realmName, clientID string) (string, error) {
+	clients, err := client.GetClients(ctx, token, realmName, gocloak.GetClientsParams{
+		ClientID: gocloak.StringP(clientID),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get client %s: %w", clientID, err)
+	}
+	if len(clients) == 0 {
+		return "", fmt.Errorf("client %s not found in realm %s", clientID, realmName)
+	}
+	return gocloak.PString(clients[0].ID), nil
+}
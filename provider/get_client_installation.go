@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// GetClientInstallation returns an installation document for a client, such as keycloak.json
+type GetClientInstallation struct{}
+
+type GetClientInstallationArgs struct {
+	RealmID    string `pulumi:"realmId"`
+	ClientID   string `pulumi:"clientId"`
+	ProviderID string `pulumi:"providerId,optional"`
+}
+
+type GetClientInstallationResult struct {
+	Content string `pulumi:"content" provider:"secret"`
+}
+
+// Annotate provides schema documentation for the getClientInstallation function
+func (g *GetClientInstallation) Annotate(a infer.Annotator) {
+	a.Describe(&g, "Returns the installation document (adapter configuration) of a client")
+}
+
+func (args *GetClientInstallationArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.ClientID, "The clientId of the client, as used in OAuth requests")
+	a.Describe(&args.ProviderID, "The installation format, e.g. keycloak-oidc-keycloak-json, keycloak-oidc-jboss-subsystem, "+
+		"keycloak-saml, saml-idp-descriptor or mod-auth-mellon")
+
+	a.SetDefault(&args.ProviderID, "keycloak-oidc-keycloak-json")
+}
+
+func (result *GetClientInstallationResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.Content, "The installation document. It is a secret because it can contain the client secret")
+}
+
+func (g *GetClientInstallation) Invoke(ctx context.Context, req infer.FunctionRequest[GetClientInstallationArgs]) (infer.FunctionResponse[GetClientInstallationResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[GetClientInstallationResult]{}, err
+	}
+
+	idOfClient, err := clientUUID(ctx, client, token, req.Input.RealmID, req.Input.ClientID)
+	if err != nil {
+		return infer.FunctionResponse[GetClientInstallationResult]{}, err
+	}
+
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetHeader("Accept", "*/*").
+		Get(adminRealmURL(ctx, req.Input.RealmID, "clients", idOfClient, "installation", "providers", req.Input.ProviderID))
+	if err := checkResponse(resp, err); err != nil {
+		return infer.FunctionResponse[GetClientInstallationResult]{}, fmt.Errorf("failed to get %s installation of client %s: %w", req.Input.ProviderID, req.Input.ClientID, err)
+	}
+
+	return infer.FunctionResponse[GetClientInstallationResult]{
+		Output: GetClientInstallationResult{Content: resp.String()},
+	}, nil
+}
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// GetClientServiceAccountUser returns the service account user of a client
+type GetClientServiceAccountUser struct{}
+
+type GetClientServiceAccountUserArgs struct {
+	RealmID  string `pulumi:"realmId"`
+	ClientID string `pulumi:"clientId"`
+}
+
+// Annotate provides schema documentation for the getClientServiceAccountUser function
+func (g *GetClientServiceAccountUser) Annotate(a infer.Annotator) {
+	a.Describe(&g, "Returns the service account user of a client with service accounts enabled")
+}
+
+func (args *GetClientServiceAccountUserArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.ClientID, "The clientId of the client, as used in OAuth requests")
+}
+
+func (g *GetClientServiceAccountUser) Invoke(ctx context.Context, req infer.FunctionRequest[GetClientServiceAccountUserArgs]) (infer.FunctionResponse[UserResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[UserResult]{}, err
+	}
+
+	idOfClient, err := clientUUID(ctx, client, token, req.Input.RealmID, req.Input.ClientID)
+	if err != nil {
+		return infer.FunctionResponse[UserResult]{}, err
+	}
+
+	user, err := client.GetClientServiceAccount(ctx, token, req.Input.RealmID, idOfClient)
+	if err != nil {
+		return infer.FunctionResponse[UserResult]{}, fmt.Errorf("failed to get service account of client %s: %w", req.Input.ClientID, err)
+	}
+
+	return infer.FunctionResponse[UserResult]{
+		Output: userResult(user),
+	}, nil
+}
@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// GetComponent looks up a realm component, such as a user federation provider, by type and name
+type GetComponent struct{}
+
+type GetComponentArgs struct {
+	RealmID      string  `pulumi:"realmId"`
+	ProviderType string  `pulumi:"providerType"`
+	Name         string  `pulumi:"name"`
+	ParentID     *string `pulumi:"parentId,optional"`
+}
+
+type GetComponentResult struct {
+	ComponentID  string              `pulumi:"componentId"`
+	Name         string              `pulumi:"name"`
+	ProviderID   string              `pulumi:"providerId"`
+	ProviderType string              `pulumi:"providerType"`
+	ParentID     string              `pulumi:"parentId"`
+	Config       map[string][]string `pulumi:"config"`
+}
+
+// componentRepresentation is the admin API wire format of a component
+type componentRepresentation struct {
+	ID           string              `json:"id"`
+	Name         string              `json:"name"`
+	ProviderID   string              `json:"providerId"`
+	ProviderType string              `json:"providerType"`
+	ParentID     string              `json:"parentId"`
+	Config       map[string][]string `json:"config"`
+}
+
+// Annotate provides schema documentation for the getComponent function
+func (g *GetComponent) Annotate(a infer.Annotator) {
+	a.Describe(&g, "Looks up a realm component, such as an LDAP user federation provider, by provider type and name")
+}
+
+func (args *GetComponentArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.ProviderType, "The component type, e.g. org.keycloak.storage.UserStorageProvider or org.keycloak.keys.KeyProvider")
+	a.Describe(&args.Name, "The name of the component")
+	a.Describe(&args.ParentID, "The ID of the parent, e.g. the LDAP provider of a mapper. Defaults to any parent")
+}
+
+func (result *GetComponentResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.ComponentID, "The ID of the component")
+	a.Describe(&result.ProviderID, "The provider implementing the component, e.g. ldap")
+	a.Describe(&result.ParentID, "The ID of the parent, the realm ID for top level components")
+	a.Describe(&result.Config, "Component configuration without secret entries")
+}
+
+func (g *GetComponent) Invoke(ctx context.Context, req infer.FunctionRequest[GetComponentArgs]) (infer.FunctionResponse[GetComponentResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[GetComponentResult]{}, err
+	}
+
+	query := map[string]string{
+		"type": req.Input.ProviderType,
+		"name": req.Input.Name,
+	}
+	if req.Input.ParentID != nil {
+		query["parent"] = *req.Input.ParentID
+	}
+
+	var components []componentRepresentation
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetQueryParams(query).
+		SetResult(&components).
+		Get(adminRealmURL(ctx, req.Input.RealmID, "components"))
+	if err := checkResponse(resp, err); err != nil {
+		return infer.FunctionResponse[GetComponentResult]{}, fmt.Errorf("failed to get components: %w", err)
+	}
+
+	switch len(components) {
+	case 0:
+		return infer.FunctionResponse[GetComponentResult]{}, fmt.Errorf("component %s of type %s not found in realm %s", req.Input.Name, req.Input.ProviderType, req.Input.RealmID)
+	case 1:
+	default:
+		return infer.FunctionResponse[GetComponentResult]{}, fmt.Errorf("%d components named %s of type %s found in realm %s, set parentId to disambiguate",
+			len(components), req.Input.Name, req.Input.ProviderType, req.Input.RealmID)
+	}
+
+	component := components[0]
+	result := GetComponentResult{
+		ComponentID:  component.ID,
+		Name:         component.Name,
+		ProviderID:   component.ProviderID,
+		ProviderType: component.ProviderType,
+		ParentID:     component.ParentID,
+		Config:       map[string][]string{},
+	}
+	for key, values := range component.Config {
+		if !isSecretConfigKey(key) {
+			result.Config[key] = values
+		}
+	}
+
+	return infer.FunctionResponse[GetComponentResult]{
+		Output: result,
+	}, nil
+}
@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// GetCompositeRoles returns the flattened set of roles granted by a role
+type GetCompositeRoles struct{}
+
+type GetCompositeRolesArgs struct {
+	RealmID  string  `pulumi:"realmId"`
+	RoleName string  `pulumi:"roleName"`
+	ClientID *string `pulumi:"clientId,optional"`
+}
+
+type GetCompositeRolesResult struct {
+	RealmRoles  []string            `pulumi:"realmRoles"`
+	ClientRoles map[string][]string `pulumi:"clientRoles"`
+}
+
+// Annotate provides schema documentation for the getCompositeRoles function
+func (g *GetCompositeRoles) Annotate(a infer.Annotator) {
+	a.Describe(&g, "Returns all roles granted by a role, with nested composite roles fully resolved. The role itself is not included")
+}
+
+func (args *GetCompositeRolesArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.RoleName, "The name of the role")
+	a.Describe(&args.ClientID, "The clientId of the client owning the role. The role is a realm role when unset")
+}
+
+func (result *GetCompositeRolesResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.RealmRoles, "Names of the granted realm roles, sorted")
+	a.Describe(&result.ClientRoles, "Names of the granted client roles keyed by clientId")
+}
+
+func (g *GetCompositeRoles) Invoke(ctx context.Context, req infer.FunctionRequest[GetCompositeRolesArgs]) (infer.FunctionResponse[GetCompositeRolesResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[GetCompositeRolesResult]{}, err
+	}
+
+	var role *gocloak.Role
+	if req.Input.ClientID != nil {
+		idOfClient, err := clientUUID(ctx, client, token, req.Input.RealmID, *req.Input.ClientID)
+		if err != nil {
+			return infer.FunctionResponse[GetCompositeRolesResult]{}, err
+		}
+		role, err = client.GetClientRole(ctx, token, req.Input.RealmID, idOfClient, req.Input.RoleName)
+		if err != nil {
+			return infer.FunctionResponse[GetCompositeRolesResult]{}, fmt.Errorf("failed to get role %s of client %s: %w", req.Input.RoleName, *req.Input.ClientID, err)
+		}
+	} else {
+		role, err = client.GetRealmRole(ctx, token, req.Input.RealmID, req.Input.RoleName)
+		if err != nil {
+			return infer.FunctionResponse[GetCompositeRolesResult]{}, fmt.Errorf("failed to get realm role %s: %w", req.Input.RoleName, err)
+		}
+	}
+
+	// Walk the composite graph breadth first; roles can be reachable on several paths and cycles are allowed
+	visited := map[string]bool{gocloak.PString(role.ID): true}
+	var realmRoles []*gocloak.Role
+	clientRoles := make(map[string][]*gocloak.Role)
+	queue := []*gocloak.Role{role}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if !gocloak.PBool(current.Composite) {
+			continue
+		}
+
+		composites, err := client.GetCompositeRolesByRoleID(ctx, token, req.Input.RealmID, gocloak.PString(current.ID))
+		if err != nil {
+			return infer.FunctionResponse[GetCompositeRolesResult]{}, fmt.Errorf("failed to get composites of role %s: %w", gocloak.PString(current.Name), err)
+		}
+		for _, composite := range composites {
+			if visited[gocloak.PString(composite.ID)] {
+				continue
+			}
+			visited[gocloak.PString(composite.ID)] = true
+			queue = append(queue, composite)
+
+			if gocloak.PBool(composite.ClientRole) {
+				containerID := gocloak.PString(composite.ContainerID)
+				clientRoles[containerID] = append(clientRoles[containerID], composite)
+			} else {
+				realmRoles = append(realmRoles, composite)
+			}
+		}
+	}
+
+	result := GetCompositeRolesResult{
+		RealmRoles:  roleNames(realmRoles),
+		ClientRoles: make(map[string][]string, len(clientRoles)),
+	}
+	for idOfClient, roles := range clientRoles {
+		owner, err := client.GetClient(ctx, token, req.Input.RealmID, idOfClient)
+		if err != nil {
+			return infer.FunctionResponse[GetCompositeRolesResult]{}, fmt.Errorf("failed to get client %s: %w", idOfClient, err)
+		}
+		result.ClientRoles[gocloak.PString(owner.ClientID)] = roleNames(roles)
+	}
+
+	return infer.FunctionResponse[GetCompositeRolesResult]{
+		Output: result,
+	}, nil
+}
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// GetGroup looks up an existing group by its full path
+type GetGroup struct{}
+
+type GetGroupArgs struct {
+	RealmID string `pulumi:"realmId"`
+	Path    string `pulumi:"path"`
+}
+
+type GetGroupResult struct {
+	GroupID    string              `pulumi:"groupId"`
+	Name       string              `pulumi:"name"`
+	Path       string              `pulumi:"path"`
+	Attributes map[string][]string `pulumi:"attributes,optional"`
+}
+
+// Annotate provides schema documentation for the getGroup function
+func (g *GetGroup) Annotate(a infer.Annotator) {
+	a.Describe(&g, "Looks up an existing group by its full path")
+}
+
+func (args *GetGroupArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.Path, "The full path of the group, e.g. /eng/platform")
+}
+
+func (result *GetGroupResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.GroupID, "The ID of the group")
+	a.Describe(&result.Name, "The name of the group")
+	a.Describe(&result.Path, "The full path of the group")
+	a.Describe(&result.Attributes, "Custom group attributes")
+}
+
+func (g *GetGroup) Invoke(ctx context.Context, req infer.FunctionRequest[GetGroupArgs]) (infer.FunctionResponse[GetGroupResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[GetGroupResult]{}, err
+	}
+
+	path := strings.Trim(req.Input.Path, "/")
+	if path == "" {
+		return infer.FunctionResponse[GetGroupResult]{}, fmt.Errorf("group path must not be empty")
+	}
+
+	var group gocloak.Group
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetResult(&group).
+		Get(adminRealmURL(ctx, req.Input.RealmID, "group-by-path", path))
+	if err := checkResponse(resp, err); err != nil {
+		if isNotFound(err) {
+			return infer.FunctionResponse[GetGroupResult]{}, fmt.Errorf("group /%s not found in realm %s", path, req.Input.RealmID)
+		}
+		return infer.FunctionResponse[GetGroupResult]{}, fmt.Errorf("failed to get group /%s: %w", path, err)
+	}
+
+	result := GetGroupResult{
+		GroupID: gocloak.PString(group.ID),
+		Name:    gocloak.PString(group.Name),
+		Path:    gocloak.PString(group.Path),
+	}
+	if group.Attributes != nil {
+		result.Attributes = *group.Attributes
+	}
+
+	return infer.FunctionResponse[GetGroupResult]{
+		Output: result,
+	}, nil
+}
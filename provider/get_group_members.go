@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// GetGroupMembers lists the direct members of a group
+type GetGroupMembers struct{}
+
+type GetGroupMembersArgs struct {
+	RealmID             string `pulumi:"realmId"`
+	GroupID             string `pulumi:"groupId"`
+	BriefRepresentation *bool  `pulumi:"briefRepresentation,optional"`
+	Offset              int    `pulumi:"offset,optional"`
+	Limit               *int   `pulumi:"limit,optional"`
+	PageSize            *int   `pulumi:"pageSize,optional"`
+}
+
+type GetGroupMembersResult struct {
+	Members []UserResult `pulumi:"members"`
+}
+
+// Annotate provides schema documentation for the getGroupMembers function
+func (g *GetGroupMembers) Annotate(a infer.Annotator) {
+	a.Describe(&g, "Lists the direct members of a group. Keycloak result pages are fetched until the limit is reached")
+}
+
+func (args *GetGroupMembersArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.GroupID, "The ID of the group")
+	a.Describe(&args.BriefRepresentation, "Whether to skip user attributes, which is faster for large groups")
+	a.Describe(&args.Offset, "Number of members to skip")
+	a.Describe(&args.Limit, "Maximum number of members to return, at most 10000. When unset, all members are returned, "+
+		"and more than 10000 fail the call")
+	a.Describe(&args.PageSize, "Number of members fetched per admin API request, at most 1000. "+
+		"Larger pages need fewer requests, smaller ones put less load on Keycloak per request")
+
+	a.SetDefault(&args.BriefRepresentation, true)
+	a.SetDefault(&args.PageSize, pageSize)
+}
+
+func (result *GetGroupMembersResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.Members, "The members of the group")
+}
+
+func (g *GetGroupMembers) Invoke(ctx context.Context, req infer.FunctionRequest[GetGroupMembersArgs]) (infer.FunctionResponse[GetGroupMembersResult], error) {
+	if req.Input.Offset < 0 {
+		return infer.FunctionResponse[GetGroupMembersResult]{}, fmt.Errorf("offset must not be negative")
+	}
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[GetGroupMembersResult]{}, err
+	}
+
+	result := GetGroupMembersResult{Members: []UserResult{}}
+	err = paginateListing(req.Input.Offset, req.Input.Limit, req.Input.PageSize, func(first, max int) (int, error) {
+		users, err := client.GetGroupMembers(ctx, token, req.Input.RealmID, req.Input.GroupID, gocloak.GetGroupsParams{
+			BriefRepresentation: req.Input.BriefRepresentation,
+			First:               gocloak.IntP(first),
+			Max:                 gocloak.IntP(max),
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to get members of group %s: %w", req.Input.GroupID, err)
+		}
+		for _, user := range users {
+			result.Members = append(result.Members, userResult(user))
+		}
+		return len(users), nil
+	})
+	if err != nil {
+		return infer.FunctionResponse[GetGroupMembersResult]{}, err
+	}
+
+	return infer.FunctionResponse[GetGroupMembersResult]{
+		Output: result,
+	}, nil
+}
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// GetIdentityProvider looks up an identity provider by alias
+type GetIdentityProvider struct{}
+
+type GetIdentityProviderArgs struct {
+	RealmID        string `pulumi:"realmId"`
+	Alias          string `pulumi:"alias"`
+	IncludeSecrets *bool  `pulumi:"includeSecrets,optional"`
+}
+
+type GetIdentityProviderResult struct {
+	InternalID                string            `pulumi:"internalId"`
+	Alias                     string            `pulumi:"alias"`
+	DisplayName               *string           `pulumi:"displayName,optional"`
+	ProviderID                string            `pulumi:"providerId"`
+	Enabled                   bool              `pulumi:"enabled"`
+	TrustEmail                bool              `pulumi:"trustEmail"`
+	StoreToken                bool              `pulumi:"storeToken"`
+	LinkOnly                  bool              `pulumi:"linkOnly"`
+	FirstBrokerLoginFlowAlias *string           `pulumi:"firstBrokerLoginFlowAlias,optional"`
+	PostBrokerLoginFlowAlias  *string           `pulumi:"postBrokerLoginFlowAlias,optional"`
+	Config                    map[string]string `pulumi:"config"`
+	SecretConfig              map[string]string `pulumi:"secretConfig,optional" provider:"secret"`
+}
+
+// Annotate provides schema documentation for the getIdentityProvider function
+func (g *GetIdentityProvider) Annotate(a infer.Annotator) {
+	a.Describe(&g, "Looks up an identity provider by alias")
+}
+
+func (args *GetIdentityProviderArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.Alias, "The alias of the identity provider")
+	a.Describe(&args.IncludeSecrets, "Whether to return secret config entries such as clientSecret in secretConfig")
+}
+
+func (result *GetIdentityProviderResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.InternalID, "The internal ID of the identity provider")
+	a.Describe(&result.ProviderID, "The provider type, e.g. oidc, saml or github")
+	a.Describe(&result.Config, "Provider configuration without secret entries")
+	a.Describe(&result.SecretConfig, "Secret configuration entries, only set when includeSecrets is true. "+
+		"Recent Keycloak versions return masked values for secrets")
+}
+
+func (g *GetIdentityProvider) Invoke(ctx context.Context, req infer.FunctionRequest[GetIdentityProviderArgs]) (infer.FunctionResponse[GetIdentityProviderResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[GetIdentityProviderResult]{}, err
+	}
+
+	idp, err := client.GetIdentityProvider(ctx, token, req.Input.RealmID, req.Input.Alias)
+	if err != nil {
+		return infer.FunctionResponse[GetIdentityProviderResult]{}, fmt.Errorf("failed to get identity provider %s: %w", req.Input.Alias, err)
+	}
+
+	result := GetIdentityProviderResult{
+		InternalID:                gocloak.PString(idp.InternalID),
+		Alias:                     gocloak.PString(idp.Alias),
+		DisplayName:               idp.DisplayName,
+		ProviderID:                gocloak.PString(idp.ProviderID),
+		Enabled:                   gocloak.PBool(idp.Enabled),
+		TrustEmail:                gocloak.PBool(idp.TrustEmail),
+		StoreToken:                gocloak.PBool(idp.StoreToken),
+		LinkOnly:                  gocloak.PBool(idp.LinkOnly),
+		FirstBrokerLoginFlowAlias: idp.FirstBrokerLoginFlowAlias,
+		PostBrokerLoginFlowAlias:  idp.PostBrokerLoginFlowAlias,
+		Config:                    map[string]string{},
+	}
+	if idp.Config != nil {
+		for key, value := range *idp.Config {
+			if !isSecretConfigKey(key) {
+				result.Config[key] = value
+				continue
+			}
+			if gocloak.PBool(req.Input.IncludeSecrets) {
+				if result.SecretConfig == nil {
+					result.SecretConfig = map[string]string{}
+				}
+				result.SecretConfig[key] = value
+			}
+		}
+	}
+
+	return infer.FunctionResponse[GetIdentityProviderResult]{
+		Output: result,
+	}, nil
+}
+
+// isSecretConfigKey reports whether a component or provider config entry holds a secret
+func isSecretConfigKey(key string) bool {
+	key = strings.ToLower(key)
+	return strings.Contains(key, "secret") || strings.Contains(key, "password") || strings.Contains(key, "credential")
+}
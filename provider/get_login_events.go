@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// GetLoginEvents queries the user (login) events of a realm
+type GetLoginEvents struct{}
+
+type GetLoginEventsArgs struct {
+	RealmID   string   `pulumi:"realmId"`
+	Types     []string `pulumi:"types,optional"`
+	UserID    *string  `pulumi:"userId,optional"`
+	ClientID  *string  `pulumi:"clientId,optional"`
+	IpAddress *string  `pulumi:"ipAddress,optional"`
+	DateFrom  *string  `pulumi:"dateFrom,optional"`
+	DateTo    *string  `pulumi:"dateTo,optional"`
+	Offset    int      `pulumi:"offset,optional"`
+	Limit     *int     `pulumi:"limit,optional"`
+	PageSize  *int     `pulumi:"pageSize,optional"`
+}
+
+type GetLoginEventsResult struct {
+	Events []LoginEvent `pulumi:"events"`
+}
+
+// LoginEvent describes a user event such as LOGIN or LOGIN_ERROR
+type LoginEvent struct {
+	Time      string            `pulumi:"time"`
+	Type      string            `pulumi:"type"`
+	ClientID  *string           `pulumi:"clientId,optional"`
+	UserID    *string           `pulumi:"userId,optional"`
+	SessionID *string           `pulumi:"sessionId,optional"`
+	IpAddress *string           `pulumi:"ipAddress,optional"`
+	Error     *string           `pulumi:"error,optional"`
+	Details   map[string]string `pulumi:"details,optional"`
+}
+
+// loginEventRepresentation is the admin API wire format of a user event
+type loginEventRepresentation struct {
+	Time      int64             `json:"time"`
+	Type      string            `json:"type"`
+	ClientID  string            `json:"clientId"`
+	UserID    string            `json:"userId"`
+	SessionID string            `json:"sessionId"`
+	IpAddress string            `json:"ipAddress"`
+	Error     string            `json:"error"`
+	Details   map[string]string `json:"details"`
+}
+
+// Annotate provides schema documentation for the getLoginEvents function
+func (g *GetLoginEvents) Annotate(a infer.Annotator) {
+	a.Describe(&g, "Queries the user events of a realm, newest first. Saving events must be enabled in the realm's event settings")
+}
+
+func (args *GetLoginEventsArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.Types, "Only return these event types, e.g. LOGIN, LOGIN_ERROR or LOGOUT")
+	a.Describe(&args.UserID, "Only return events of this user ID")
+	a.Describe(&args.ClientID, "Only return events of this clientId")
+	a.Describe(&args.IpAddress, "Only return events from this IP address")
+	a.Describe(&args.DateFrom, "Only return events on or after this date, in yyyy-MM-dd format")
+	a.Describe(&args.DateTo, "Only return events on or before this date, in yyyy-MM-dd format")
+	a.Describe(&args.Offset, "Number of matching events to skip")
+	a.Describe(&args.Limit, "Maximum number of events to return, at most 10000")
+	a.Describe(&args.PageSize, "Number of events fetched per admin API request, at most 1000. "+
+		"Larger pages need fewer requests, smaller ones put less load on Keycloak per request")
+
+	a.SetDefault(&args.Limit, 100)
+	a.SetDefault(&args.PageSize, pageSize)
+}
+
+func (result *GetLoginEventsResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.Events, "The matching events")
+}
+
+func (e *LoginEvent) Annotate(a infer.Annotator) {
+	a.Describe(&e.Time, "When the event happened, in RFC 3339 format")
+	a.Describe(&e.Type, "The event type")
+	a.Describe(&e.ClientID, "The clientId of the client involved")
+	a.Describe(&e.UserID, "The ID of the user involved")
+	a.Describe(&e.SessionID, "The ID of the user session")
+	a.Describe(&e.IpAddress, "The IP address of the user")
+	a.Describe(&e.Error, "The error, for error events")
+	a.Describe(&e.Details, "Additional event details such as username or redirect_uri")
+}
+
+func (g *GetLoginEvents) Invoke(ctx context.Context, req infer.FunctionRequest[GetLoginEventsArgs]) (infer.FunctionResponse[GetLoginEventsResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[GetLoginEventsResult]{}, err
+	}
+
+	query := url.Values{}
+	for _, eventType := range req.Input.Types {
+		query.Add("type", eventType)
+	}
+	for key, value := range map[string]*string{
+		"user":      req.Input.UserID,
+		"client":    req.Input.ClientID,
+		"ipAddress": req.Input.IpAddress,
+		"dateFrom":  req.Input.DateFrom,
+		"dateTo":    req.Input.DateTo,
+	} {
+		if value != nil {
+			query.Set(key, *value)
+		}
+	}
+
+	result := GetLoginEventsResult{Events: []LoginEvent{}}
+	err = paginateListing(req.Input.Offset, req.Input.Limit, req.Input.PageSize, func(first, max int) (int, error) {
+		query.Set("first", strconv.Itoa(first))
+		query.Set("max", strconv.Itoa(max))
+
+		var events []loginEventRepresentation
+		resp, err := client.GetRequestWithBearerAuth(ctx, token).
+			SetQueryParamsFromValues(query).
+			SetResult(&events).
+			Get(adminRealmURL(ctx, req.Input.RealmID, "events"))
+		if err := checkResponse(resp, err); err != nil {
+			return 0, fmt.Errorf("failed to get events: %w", err)
+		}
+
+		for _, event := range events {
+			result.Events = append(result.Events, LoginEvent{
+				Time:      formatMillis(&event.Time),
+				Type:      event.Type,
+				ClientID:  optionalString(event.ClientID),
+				UserID:    optionalString(event.UserID),
+				SessionID: optionalString(event.SessionID),
+				IpAddress: optionalString(event.IpAddress),
+				Error:     optionalString(event.Error),
+				Details:   event.Details,
+			})
+		}
+		return len(events), nil
+	})
+	if err != nil {
+		return infer.FunctionResponse[GetLoginEventsResult]{}, err
+	}
+
+	return infer.FunctionResponse[GetLoginEventsResult]{
+		Output: result,
+	}, nil
+}
@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// GetRealm looks up an existing realm by name
+type GetRealm struct{}
+
+type GetRealmArgs struct {
+	Name string `pulumi:"name"`
+}
+
+// Annotate provides schema documentation for the getRealm function
+func (g *GetRealm) Annotate(a infer.Annotator) {
+	a.Describe(&g, "Looks up an existing realm by name and returns the settings this provider manages")
+}
+
+func (args *GetRealmArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Name, "The name of the realm")
+}
+
+func (g *GetRealm) Invoke(ctx context.Context, req infer.FunctionRequest[GetRealmArgs]) (infer.FunctionResponse[RealmState], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[RealmState]{}, err
+	}
+
+	state, err := readRealmState(ctx, client, token, req.Input.Name)
+	if err != nil {
+		return infer.FunctionResponse[RealmState]{}, err
+	}
+
+	return infer.FunctionResponse[RealmState]{
+		Output: state,
+	}, nil
+}
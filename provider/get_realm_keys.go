@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// GetRealmKeys returns the keys of a realm with their public keys and certificates
+type GetRealmKeys struct{}
+
+type GetRealmKeysArgs struct {
+	RealmID   string  `pulumi:"realmId"`
+	Algorithm *string `pulumi:"algorithm,optional"`
+	Use       *string `pulumi:"use,optional"`
+	Status    *string `pulumi:"status,optional"`
+}
+
+type GetRealmKeysResult struct {
+	Keys   []RealmKey        `pulumi:"keys"`
+	Active map[string]string `pulumi:"active"`
+}
+
+// RealmKey describes a single key of a realm
+type RealmKey struct {
+	Kid         string  `pulumi:"kid"`
+	Algorithm   string  `pulumi:"algorithm"`
+	Use         string  `pulumi:"use"`
+	Type        string  `pulumi:"type"`
+	Status      string  `pulumi:"status"`
+	ProviderID  string  `pulumi:"providerId"`
+	Priority    int     `pulumi:"priority"`
+	PublicKey   *string `pulumi:"publicKey,optional"`
+	Certificate *string `pulumi:"certificate,optional"`
+}
+
+// keysMetadataRepresentation is the admin API wire format of the realm keys endpoint
+type keysMetadataRepresentation struct {
+	Active map[string]string `json:"active"`
+	Keys   []struct {
+		Kid              string `json:"kid"`
+		Algorithm        string `json:"algorithm"`
+		Use              string `json:"use"`
+		Type             string `json:"type"`
+		Status           string `json:"status"`
+		ProviderID       string `json:"providerId"`
+		ProviderPriority int    `json:"providerPriority"`
+		PublicKey        string `json:"publicKey"`
+		Certificate      string `json:"certificate"`
+	} `json:"keys"`
+}
+
+// Annotate provides schema documentation for the getRealmKeys function
+func (g *GetRealmKeys) Annotate(a infer.Annotator) {
+	a.Describe(&g, "Returns the keys of a realm, including the public keys and certificates of asymmetric keys")
+}
+
+func (args *GetRealmKeysArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.Algorithm, "Only return keys for this algorithm, e.g. RS256")
+	a.Describe(&args.Use, "Only return keys with this use, SIG or ENC")
+	a.Describe(&args.Status, "Only return keys with this status: ACTIVE, PASSIVE or DISABLED")
+
+	a.SetDefault(&args.Status, "ACTIVE")
+}
+
+func (result *GetRealmKeysResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.Keys, "The matching keys, ordered as returned by Keycloak")
+	a.Describe(&result.Active, "Key IDs of the keys currently used for each algorithm")
+}
+
+func (key *RealmKey) Annotate(a infer.Annotator) {
+	a.Describe(&key.Kid, "The key ID")
+	a.Describe(&key.Algorithm, "The algorithm of the key")
+	a.Describe(&key.Use, "The use of the key, SIG or ENC")
+	a.Describe(&key.Type, "The key type, e.g. RSA, EC or OCT")
+	a.Describe(&key.Status, "The status of the key")
+	a.Describe(&key.ProviderID, "The ID of the key provider component")
+	a.Describe(&key.Priority, "The priority of the key provider")
+	a.Describe(&key.PublicKey, "Base64 encoded public key")
+	a.Describe(&key.Certificate, "Base64 encoded X.509 certificate")
+}
+
+func (g *GetRealmKeys) Invoke(ctx context.Context, req infer.FunctionRequest[GetRealmKeysArgs]) (infer.FunctionResponse[GetRealmKeysResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[GetRealmKeysResult]{}, err
+	}
+
+	var metadata keysMetadataRepresentation
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetResult(&metadata).
+		Get(adminRealmURL(ctx, req.Input.RealmID, "keys"))
+	if err := checkResponse(resp, err); err != nil {
+		return infer.FunctionResponse[GetRealmKeysResult]{}, fmt.Errorf("failed to get realm keys: %w", err)
+	}
+
+	result := GetRealmKeysResult{
+		Keys:   []RealmKey{},
+		Active: metadata.Active,
+	}
+	if result.Active == nil {
+		result.Active = map[string]string{}
+	}
+
+	matches := func(filter *string, value string) bool {
+		return filter == nil || *filter == value
+	}
+	for _, key := range metadata.Keys {
+		if !matches(req.Input.Algorithm, key.Algorithm) || !matches(req.Input.Use, key.Use) || !matches(req.Input.Status, key.Status) {
+			continue
+		}
+		result.Keys = append(result.Keys, RealmKey{
+			Kid:         key.Kid,
+			Algorithm:   key.Algorithm,
+			Use:         key.Use,
+			Type:        key.Type,
+			Status:      key.Status,
+			ProviderID:  key.ProviderID,
+			Priority:    key.ProviderPriority,
+			PublicKey:   optionalString(key.PublicKey),
+			Certificate: optionalString(key.Certificate),
+		})
+	}
+
+	return infer.FunctionResponse[GetRealmKeysResult]{
+		Output: result,
+	}, nil
+}
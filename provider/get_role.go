@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// GetRealmRole looks up a realm role by name
+type GetRealmRole struct{}
+
+type GetRealmRoleArgs struct {
+	RealmID string `pulumi:"realmId"`
+	Name    string `pulumi:"name"`
+}
+
+// GetClientRole looks up a client role by name
+type GetClientRole struct{}
+
+type GetClientRoleArgs struct {
+	RealmID  string `pulumi:"realmId"`
+	ClientID string `pulumi:"clientId"`
+	Name     string `pulumi:"name"`
+}
+
+// RoleResult describes a role returned by the role lookup functions
+type RoleResult struct {
+	RoleID      string              `pulumi:"roleId"`
+	Name        string              `pulumi:"name"`
+	Description *string             `pulumi:"description,optional"`
+	Composite   bool                `pulumi:"composite"`
+	Attributes  map[string][]string `pulumi:"attributes,optional"`
+}
+
+// Annotate provides schema documentation for the getRealmRole function
+func (g *GetRealmRole) Annotate(a infer.Annotator) {
+	a.Describe(&g, "Looks up a realm role by name")
+}
+
+func (args *GetRealmRoleArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.Name, "The name of the role")
+}
+
+// Annotate provides schema documentation for the getClientRole function
+func (g *GetClientRole) Annotate(a infer.Annotator) {
+	a.Describe(&g, "Looks up a client role by name")
+}
+
+func (args *GetClientRoleArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.ClientID, "The clientId of the client owning the role, as used in OAuth requests")
+	a.Describe(&args.Name, "The name of the role")
+}
+
+func (result *RoleResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.RoleID, "The ID (UUID) of the role")
+	a.Describe(&result.Name, "The name of the role")
+	a.Describe(&result.Description, "The description of the role")
+	a.Describe(&result.Composite, "Whether the role is a composite role")
+	a.Describe(&result.Attributes, "Custom role attributes")
+}
+
+func (g *GetRealmRole) Invoke(ctx context.Context, req infer.FunctionRequest[GetRealmRoleArgs]) (infer.FunctionResponse[RoleResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[RoleResult]{}, err
+	}
+
+	role, err := client.GetRealmRole(ctx, token, req.Input.RealmID, req.Input.Name)
+	if err != nil {
+		return infer.FunctionResponse[RoleResult]{}, fmt.Errorf("failed to get realm role %s: %w", req.Input.Name, err)
+	}
+
+	return infer.FunctionResponse[RoleResult]{
+		Output: roleResult(role),
+	}, nil
+}
+
+func (g *GetClientRole) Invoke(ctx context.Context, req infer.FunctionRequest[GetClientRoleArgs]) (infer.FunctionResponse[RoleResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[RoleResult]{}, err
+	}
+
+	idOfClient, err := clientUUID(ctx, client, token, req.Input.RealmID, req.Input.ClientID)
+	if err != nil {
+		return infer.FunctionResponse[RoleResult]{}, err
+	}
+
+	role, err := client.GetClientRole(ctx, token, req.Input.RealmID, idOfClient, req.Input.Name)
+	if err != nil {
+		return infer.FunctionResponse[RoleResult]{}, fmt.Errorf("failed to get role %s of client %s: %w", req.Input.Name, req.Input.ClientID, err)
+	}
+
+	return infer.FunctionResponse[RoleResult]{
+		Output: roleResult(role),
+	}, nil
+}
+
+func roleResult(role *gocloak.Role) RoleResult {
+	result := RoleResult{
+		RoleID:      gocloak.PString(role.ID),
+		Name:        gocloak.PString(role.Name),
+		Description: role.Description,
+		Composite:   gocloak.PBool(role.Composite),
+	}
+	if role.Attributes != nil {
+		result.Attributes = *role.Attributes
+	}
+	return result
+}
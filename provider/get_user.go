@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// GetUser looks up an existing user by username or email
+type GetUser struct{}
+
+type GetUserArgs struct {
+	RealmID  string  `pulumi:"realmId"`
+	Username *string `pulumi:"username,optional"`
+	Email    *string `pulumi:"email,optional"`
+}
+
+// UserResult describes a user returned by the user lookup functions
+type UserResult struct {
+	UserID        string              `pulumi:"userId"`
+	Username      string              `pulumi:"username"`
+	Email         *string             `pulumi:"email,optional"`
+	FirstName     *string             `pulumi:"firstName,optional"`
+	LastName      *string             `pulumi:"lastName,optional"`
+	Enabled       bool                `pulumi:"enabled"`
+	EmailVerified bool                `pulumi:"emailVerified"`
+	Attributes    map[string][]string `pulumi:"attributes,optional"`
+}
+
+// Annotate provides schema documentation for the getUser function
+func (g *GetUser) Annotate(a infer.Annotator) {
+	a.Describe(&g, "Looks up an existing user by exact username or email")
+}
+
+func (args *GetUserArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.Username, "The username to look up")
+	a.Describe(&args.Email, "The email address to look up, when no username is given")
+}
+
+func (result *UserResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.UserID, "The ID of the user")
+	a.Describe(&result.Username, "The username")
+	a.Describe(&result.Email, "The email address")
+	a.Describe(&result.Enabled, "Whether the user is enabled")
+	a.Describe(&result.EmailVerified, "Whether the email address is verified")
+	a.Describe(&result.Attributes, "Custom user attributes")
+}
+
+func (g *GetUser) Invoke(ctx context.Context, req infer.FunctionRequest[GetUserArgs]) (infer.FunctionResponse[UserResult], error) {
+	params := gocloak.GetUsersParams{Exact: gocloak.BoolP(true)}
+	lookup := ""
+	switch {
+	case req.Input.Username != nil:
+		params.Username = req.Input.Username
+		lookup = "username " + *req.Input.Username
+	case req.Input.Email != nil:
+		params.Email = req.Input.Email
+		lookup = "email " + *req.Input.Email
+	default:
+		return infer.FunctionResponse[UserResult]{}, fmt.Errorf("either username or email must be set")
+	}
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[UserResult]{}, err
+	}
+
+	users, err := client.GetUsers(ctx, token, req.Input.RealmID, params)
+	if err != nil {
+		return infer.FunctionResponse[UserResult]{}, fmt.Errorf("failed to get user with %s: %w", lookup, err)
+	}
+	if len(users) == 0 {
+		return infer.FunctionResponse[UserResult]{}, fmt.Errorf("no user with %s found in realm %s", lookup, req.Input.RealmID)
+	}
+	if len(users) > 1 {
+		return infer.FunctionResponse[UserResult]{}, fmt.Errorf("%d users with %s found in realm %s", len(users), lookup, req.Input.RealmID)
+	}
+
+	return infer.FunctionResponse[UserResult]{
+		Output: userResult(users[0]),
+	}, nil
+}
+
+func userResult(user *gocloak.User) UserResult {
+	result := UserResult{
+		UserID:        gocloak.PString(user.ID),
+		Username:      gocloak.PString(user.Username),
+		Email:         user.Email,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Enabled:       gocloak.PBool(user.Enabled),
+		EmailVerified: gocloak.PBool(user.EmailVerified),
+	}
+	if user.Attributes != nil {
+		result.Attributes = *user.Attributes
+	}
+	return result
+}
@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// GetUserSessions lists the active sessions of a user
+type GetUserSessions struct{}
+
+type GetUserSessionsArgs struct {
+	RealmID string `pulumi:"realmId"`
+	UserID  string `pulumi:"userId"`
+}
+
+type GetUserSessionsResult struct {
+	Sessions []UserSession `pulumi:"sessions"`
+}
+
+// UserSession describes an active user session
+type UserSession struct {
+	SessionID  string   `pulumi:"sessionId"`
+	IpAddress  string   `pulumi:"ipAddress"`
+	Start      string   `pulumi:"start"`
+	LastAccess string   `pulumi:"lastAccess"`
+	Clients    []string `pulumi:"clients"`
+}
+
+// Annotate provides schema documentation for the getUserSessions function
+func (g *GetUserSessions) Annotate(a infer.Annotator) {
+	a.Describe(&g, "Lists the active sessions of a user")
+}
+
+func (args *GetUserSessionsArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.UserID, "The ID of the user")
+}
+
+func (result *GetUserSessionsResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.Sessions, "The active sessions of the user")
+}
+
+func (s *UserSession) Annotate(a infer.Annotator) {
+	a.Describe(&s.SessionID, "The ID of the session")
+	a.Describe(&s.IpAddress, "The IP address the session was started from")
+	a.Describe(&s.Start, "When the session was started, in RFC 3339 format")
+	a.Describe(&s.LastAccess, "When the session was last used, in RFC 3339 format")
+	a.Describe(&s.Clients, "The clientIds of the clients the session is used by")
+}
+
+func (g *GetUserSessions) Invoke(ctx context.Context, req infer.FunctionRequest[GetUserSessionsArgs]) (infer.FunctionResponse[GetUserSessionsResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[GetUserSessionsResult]{}, err
+	}
+
+	sessions, err := client.GetUserSessions(ctx, token, req.Input.RealmID, req.Input.UserID)
+	if err != nil {
+		return infer.FunctionResponse[GetUserSessionsResult]{}, fmt.Errorf("failed to get sessions of user %s: %w", req.Input.UserID, err)
+	}
+
+	result := GetUserSessionsResult{Sessions: []UserSession{}}
+	for _, session := range sessions {
+		clients := []string{}
+		if session.Clients != nil {
+			for _, clientID := range *session.Clients {
+				clients = append(clients, clientID)
+			}
+			sort.Strings(clients)
+		}
+		result.Sessions = append(result.Sessions, UserSession{
+			SessionID:  gocloak.PString(session.ID),
+			IpAddress:  gocloak.PString(session.IPAddress),
+			Start:      formatMillis(session.Start),
+			LastAccess: formatMillis(session.LastAccess),
+			Clients:    clients,
+		})
+	}
+
+	return infer.FunctionResponse[GetUserSessionsResult]{
+		Output: result,
+	}, nil
+}
+
+// formatMillis formats a Keycloak timestamp in milliseconds since the epoch as RFC 3339
+func formatMillis(millis *int64) string {
+	if millis == nil {
+		return ""
+	}
+	return time.UnixMilli(*millis).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,182 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// GroupPermissions enables fine-grained admin permissions for a group
+// and attaches policies to the individual permission scopes.
+type GroupPermissions struct{}
+
+type GroupPermissionsArgs struct {
+	RealmID               string                `pulumi:"realmId" provider:"replaceOnChanges"`
+	GroupID               string                `pulumi:"groupId" provider:"replaceOnChanges"`
+	ViewScope             *AdminPermissionScope `pulumi:"viewScope,optional"`
+	ManageScope           *AdminPermissionScope `pulumi:"manageScope,optional"`
+	ViewMembersScope      *AdminPermissionScope `pulumi:"viewMembersScope,optional"`
+	ManageMembersScope    *AdminPermissionScope `pulumi:"manageMembersScope,optional"`
+	ManageMembershipScope *AdminPermissionScope `pulumi:"manageMembershipScope,optional"`
+}
+
+type GroupPermissionsState struct {
+	GroupPermissionsArgs
+}
+
+// Annotate provides schema documentation for the GroupPermissions resource
+func (g *GroupPermissions) Annotate(a infer.Annotator) {
+	a.Describe(&g, "Fine-grained admin permissions for a group. Destroying the resource disables the permissions")
+}
+
+func (args *GroupPermissionsArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.GroupID, "The ID of the group")
+	a.Describe(&args.ViewScope, "Policies allowed to view the group")
+	a.Describe(&args.ManageScope, "Policies allowed to manage the group")
+	a.Describe(&args.ViewMembersScope, "Policies allowed to view the members of the group")
+	a.Describe(&args.ManageMembersScope, "Policies allowed to manage the members of the group")
+	a.Describe(&args.ManageMembershipScope, "Policies allowed to add and remove members of the group")
+}
+
+// scopes returns the configured scopes keyed by Keycloak scope name
+func (args GroupPermissionsArgs) scopes() map[string]*AdminPermissionScope {
+	return map[string]*AdminPermissionScope{
+		"view":              args.ViewScope,
+		"manage":            args.ManageScope,
+		"view-members":      args.ViewMembersScope,
+		"manage-members":    args.ManageMembersScope,
+		"manage-membership": args.ManageMembershipScope,
+	}
+}
+
+func (args GroupPermissionsArgs) permissionsURL(ctx context.Context) string {
+	return adminRealmURL(ctx, args.RealmID, "groups", args.GroupID, "management", "permissions")
+}
+
+func (g *GroupPermissions) Create(ctx context.Context, req infer.CreateRequest[GroupPermissionsArgs]) (infer.CreateResponse[GroupPermissionsState], error) {
+	id := req.Inputs.RealmID + "/" + req.Inputs.GroupID
+
+	if req.DryRun {
+		return infer.CreateResponse[GroupPermissionsState]{
+			ID:     id,
+			Output: GroupPermissionsState{req.Inputs},
+		}, nil
+	}
+
+	state, err := applyGroupPermissions(ctx, req.Inputs)
+	if err != nil {
+		return infer.CreateResponse[GroupPermissionsState]{}, err
+	}
+
+	return infer.CreateResponse[GroupPermissionsState]{
+		ID:     id,
+		Output: state,
+	}, nil
+}
+
+func (g *GroupPermissions) Update(ctx context.Context, req infer.UpdateRequest[GroupPermissionsArgs, GroupPermissionsState]) (infer.UpdateResponse[GroupPermissionsState], error) {
+	if req.DryRun {
+		return infer.UpdateResponse[GroupPermissionsState]{
+			Output: GroupPermissionsState{req.Inputs},
+		}, nil
+	}
+
+	state, err := applyGroupPermissions(ctx, req.Inputs)
+	if err != nil {
+		return infer.UpdateResponse[GroupPermissionsState]{}, err
+	}
+
+	return infer.UpdateResponse[GroupPermissionsState]{
+		Output: state,
+	}, nil
+}
+
+func (g *GroupPermissions) Delete(ctx context.Context, req infer.DeleteRequest[GroupPermissionsState]) (infer.DeleteResponse, error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
+	_, err = setManagementPermissions(ctx, client, token, req.State.permissionsURL(ctx), false)
+	if err != nil && !isNotFound(err) {
+		return infer.DeleteResponse{}, err
+	}
+
+	return infer.DeleteResponse{}, nil
+}
+
+func (g *GroupPermissions) Read(ctx context.Context, req infer.ReadRequest[GroupPermissionsArgs, GroupPermissionsState]) (infer.ReadResponse[GroupPermissionsArgs, GroupPermissionsState], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.ReadResponse[GroupPermissionsArgs, GroupPermissionsState]{}, err
+	}
+
+	args := req.State.GroupPermissionsArgs
+	if realmName, groupID, ok := strings.Cut(req.ID, "/"); ok {
+		args.RealmID = realmName
+		args.GroupID = groupID
+	}
+
+	permissionIDs, err := getManagementPermissions(ctx, client, token, args.permissionsURL(ctx))
+	if err != nil && !isNotFound(err) {
+		return infer.ReadResponse[GroupPermissionsArgs, GroupPermissionsState]{}, fmt.Errorf("failed to get group management permissions: %w", err)
+	}
+	if permissionIDs == nil {
+		return infer.ReadResponse[GroupPermissionsArgs, GroupPermissionsState]{}, nil
+	}
+
+	scopes := args.scopes()
+	if req.State.RealmID == "" {
+		scopes = importedScopes(scopes)
+	}
+	state, err := readGroupPermissionsState(ctx, client, token, args, permissionIDs, scopes)
+	if err != nil {
+		return infer.ReadResponse[GroupPermissionsArgs, GroupPermissionsState]{}, err
+	}
+
+	return infer.ReadResponse[GroupPermissionsArgs, GroupPermissionsState]{
+		ID:     req.ID,
+		Inputs: state.GroupPermissionsArgs,
+		State:  state,
+	}, nil
+}
+
+func applyGroupPermissions(ctx context.Context, args GroupPermissionsArgs) (GroupPermissionsState, error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return GroupPermissionsState{}, err
+	}
+
+	permissionIDs, err := setManagementPermissions(ctx, client, token, args.permissionsURL(ctx), true)
+	if err != nil {
+		return GroupPermissionsState{}, err
+	}
+
+	if err := applyScopePermissions(ctx, client, token, args.RealmID, permissionIDs, args.scopes()); err != nil {
+		return GroupPermissionsState{}, err
+	}
+
+	return readGroupPermissionsState(ctx, client, token, args, permissionIDs, args.scopes())
+}
+
+func readGroupPermissionsState(ctx context.Context, client KeycloakClient, token string, args GroupPermissionsArgs, permissionIDs map[string]string, configured map[string]*AdminPermissionScope) (GroupPermissionsState, error) {
+	scopes, err := readScopePermissions(ctx, client, token, args.RealmID, permissionIDs, configured)
+	if err != nil {
+		return GroupPermissionsState{}, err
+	}
+
+	return GroupPermissionsState{
+		GroupPermissionsArgs{
+			RealmID:               args.RealmID,
+			GroupID:               args.GroupID,
+			ViewScope:             scopes["view"],
+			ManageScope:           scopes["manage"],
+			ViewMembersScope:      scopes["view-members"],
+			ManageMembersScope:    scopes["manage-members"],
+			ManageMembershipScope: scopes["manage-membership"],
+		},
+	}, nil
+}
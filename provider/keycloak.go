@@ -0,0 +1,222 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/go-resty/resty/v2"
+	"github.com/pulumi/pulumi-go-provider/infer"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/property"
+)
+
+// adminRealmURL builds an admin API URL for endpoints that gocloak does not cover
+func adminRealmURL(ctx context.Context, realm string, path ...string) string {
+	config := infer.GetConfig[ProviderConfig](ctx)
+	segments := append([]string{config.baseURL(), "admin", "realms", realm}, path...)
+	return strings.Join(segments, "/")
+}
+
+// realmURL builds a URL for the public endpoints of a realm
+func realmURL(ctx context.Context, realm string, path ...string) string {
+	config := infer.GetConfig[ProviderConfig](ctx)
+	segments := append([]string{config.baseURL(), "realms", realm}, path...)
+	return strings.Join(segments, "/")
+}
+
+// maxErrorBodyLength bounds the unstructured error bodies included in errors
+const maxErrorBodyLength = 500
+
+// checkResponse turns a failed raw admin API call into the same error type gocloak returns, with the message of
+// Keycloak's error body. Bodies that are not Keycloak errors, e.g. proxy error pages, are included when short
+func checkResponse(resp *resty.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		message := resp.Status()
+		var body gocloak.HTTPErrorResponse
+		if json.Unmarshal(resp.Body(), &body) == nil && body.NotEmpty() {
+			message = fmt.Sprintf("%s: %s", resp.Status(), body.String())
+		} else if text := strings.TrimSpace(resp.String()); text != "" && len(text) <= maxErrorBodyLength && !strings.HasPrefix(text, "<") {
+			message = fmt.Sprintf("%s: %s", resp.Status(), text)
+		}
+		return &gocloak.APIError{
+			Code:    resp.StatusCode(),
+			Message: message,
+		}
+	}
+	return nil
+}
+
+// isNotFound reports whether an admin API error is a 404
+func isNotFound(err error) bool {
+	var apiErr *gocloak.APIError
+	return errors.As(err, &apiErr) && apiErr.Code == 404
+}
+
+func isConflict(err error) bool {
+	var apiErr *gocloak.APIError
+	return errors.As(err, &apiErr) && apiErr.Code == 409
+}
+
+// withAutoName fills in an unset name input like Pulumi auto-naming does, from the resource name and a random
+// suffix, so that stacks sharing a realm do not collide. A name generated before is kept, so updates do not rename
+func withAutoName(req infer.CheckRequest) (property.Map, error) {
+	if name, ok := req.NewInputs.GetOk("name"); ok && !name.IsNull() {
+		return req.NewInputs, nil
+	}
+	if old := req.OldInputs.Get("name"); old.IsString() && old.AsString() != "" {
+		return req.NewInputs.Set("name", old), nil
+	}
+	name, err := resource.NewUniqueHex(req.Name+"-", 7, 0)
+	if err != nil {
+		return req.NewInputs, fmt.Errorf("failed to generate a name: %w", err)
+	}
+	return req.NewInputs.Set("name", property.New(name)), nil
+}
+
+// parseResourceID splits a composite resource ID such as "realm/clientId" into the parts named by format.
+// The last part may itself contain slashes
+func parseResourceID(id, format string) ([]string, error) {
+	n := strings.Count(format, "/") + 1
+	parts := strings.SplitN(id, "/", n)
+	if len(parts) != n || slices.Contains(parts, "") {
+		return nil, fmt.Errorf("invalid ID %q, expected %s", id, format)
+	}
+	return parts, nil
+}
+
+var (
+	serverVersionsMu sync.Mutex
+	serverVersions   = map[string]string{}
+)
+
+// serverVersion returns the version reported by the Keycloak server info endpoint
+func serverVersion(ctx context.Context, client KeycloakClient, token string) (string, error) {
+	return detectServerVersion(ctx, infer.GetConfig[ProviderConfig](ctx), client, token)
+}
+
+// detectServerVersion queries the server info endpoint once per server and provider process
+func detectServerVersion(ctx context.Context, config ProviderConfig, client KeycloakClient, token string) (string, error) {
+	baseURL := config.baseURL()
+
+	serverVersionsMu.Lock()
+	version, ok := serverVersions[baseURL]
+	serverVersionsMu.Unlock()
+	if ok {
+		return version, nil
+	}
+
+	var info struct {
+		SystemInfo struct {
+			Version string `json:"version"`
+		} `json:"systemInfo"`
+	}
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetResult(&info).
+		Get(baseURL + "/admin/serverinfo")
+	if err := checkResponse(resp, err); err != nil {
+		return "", fmt.Errorf("failed to get server info: %w", err)
+	}
+
+	serverVersionsMu.Lock()
+	serverVersions[baseURL] = info.SystemInfo.Version
+	serverVersionsMu.Unlock()
+
+	return info.SystemInfo.Version, nil
+}
+
+// requireServerVersion fails with a clear error when the server is older than the given major version.
+// Legacy servers are always older than the features gated on
+func requireServerVersion(ctx context.Context, client KeycloakClient, token string, major int, feature string) error {
+	// RH-SSO reports its own version numbers, which cannot be compared with Keycloak releases
+	if infer.GetConfig[ProviderConfig](ctx).isLegacy() {
+		return fmt.Errorf("%s requires Keycloak >= %d and is not available in legacy mode", feature, major)
+	}
+
+	version, err := serverVersion(ctx, client, token)
+	if err != nil {
+		return err
+	}
+
+	serverMajor := parseInt(strings.SplitN(version, ".", 2)[0])
+	if serverMajor == nil || *serverMajor < major {
+		return fmt.Errorf("%s requires Keycloak >= %d (server version is %q)", feature, major, version)
+	}
+
+	return nil
+}
+
+const (
+	// pageSize is the default number of items requested per call from paginated admin API endpoints
+	pageSize = 100
+	// maxPageSize bounds the pageSize input of lookup functions
+	maxPageSize = 1000
+	// maxListedItems caps the items a lookup function returns, so that a broad query cannot load a whole user base
+	// into the provider and the engine. Larger results are read in parts with offset and limit
+	maxListedItems = 10000
+)
+
+// paginate calls fetch for consecutive pages of the given size starting at offset until a page comes back short
+// or limit items have been fetched. fetch returns the number of items on the page.
+func paginate(offset int, limit *int, pageSize int, fetch func(first, max int) (int, error)) error {
+	fetched := 0
+	for first := offset; ; first += pageSize {
+		size := pageSize
+		if limit != nil {
+			size = min(size, *limit-fetched)
+			if size <= 0 {
+				return nil
+			}
+		}
+
+		n, err := fetch(first, size)
+		if err != nil {
+			return err
+		}
+		fetched += n
+		if n < size {
+			return nil
+		}
+	}
+}
+
+// paginateListing pages through the results of a lookup function, fetching pageSize items per call. Without a limit
+// at most maxListedItems are returned, and larger results fail instead of being silently cut off
+func paginateListing(offset int, limit, size *int, fetch func(first, max int) (int, error)) error {
+	if limit != nil && *limit > maxListedItems {
+		return fmt.Errorf("limit must not exceed %d, page through larger results with offset", maxListedItems)
+	}
+	perPage := pageSize
+	if size != nil {
+		if *size < 1 || *size > maxPageSize {
+			return fmt.Errorf("pageSize must be between 1 and %d", maxPageSize)
+		}
+		perPage = *size
+	}
+	if limit != nil {
+		return paginate(offset, limit, perPage, fetch)
+	}
+
+	// One more than the cap tells a result at the cap from a larger one
+	capped, fetched := maxListedItems+1, 0
+	err := paginate(offset, &capped, perPage, func(first, max int) (int, error) {
+		n, err := fetch(first, max)
+		fetched += n
+		return n, err
+	})
+	if err != nil {
+		return err
+	}
+	if fetched > maxListedItems {
+		return fmt.Errorf("more than %d results match: narrow the query, or page through them with offset and limit", maxListedItems)
+	}
+	return nil
+}
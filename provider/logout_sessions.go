@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// LogoutSessions logs out all sessions of a realm, a user or a client
+type LogoutSessions struct{}
+
+type LogoutSessionsArgs struct {
+	RealmID  string  `pulumi:"realmId"`
+	UserID   *string `pulumi:"userId,optional"`
+	ClientID *string `pulumi:"clientId,optional"`
+}
+
+type LogoutSessionsResult struct {
+	LoggedOutSessions *int `pulumi:"loggedOutSessions,optional"`
+}
+
+// Annotate provides schema documentation for the logoutSessions function
+func (l *LogoutSessions) Annotate(a infer.Annotator) {
+	a.Describe(&l, "Logs out sessions. Without userId or clientId all sessions of the realm are logged out, "+
+		"and a not-before policy is pushed to clients that have an admin URL")
+}
+
+func (args *LogoutSessionsArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.UserID, "Only log out the sessions of this user ID")
+	a.Describe(&args.ClientID, "Only log out the sessions used by this clientId")
+}
+
+func (result *LogoutSessionsResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.LoggedOutSessions, "Number of sessions logged out. Not reported for realm wide logouts")
+}
+
+func (l *LogoutSessions) Invoke(ctx context.Context, req infer.FunctionRequest[LogoutSessionsArgs]) (infer.FunctionResponse[LogoutSessionsResult], error) {
+	if req.Input.UserID != nil && req.Input.ClientID != nil {
+		return infer.FunctionResponse[LogoutSessionsResult]{}, fmt.Errorf("only one of userId and clientId can be set")
+	}
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[LogoutSessionsResult]{}, err
+	}
+
+	switch {
+	case req.Input.UserID != nil:
+		sessions, err := client.GetUserSessions(ctx, token, req.Input.RealmID, *req.Input.UserID)
+		if err != nil {
+			return infer.FunctionResponse[LogoutSessionsResult]{}, fmt.Errorf("failed to get sessions of user %s: %w", *req.Input.UserID, err)
+		}
+		if err := client.LogoutAllSessions(ctx, token, req.Input.RealmID, *req.Input.UserID); err != nil {
+			return infer.FunctionResponse[LogoutSessionsResult]{}, fmt.Errorf("failed to log out user %s: %w", *req.Input.UserID, err)
+		}
+		return infer.FunctionResponse[LogoutSessionsResult]{
+			Output: LogoutSessionsResult{LoggedOutSessions: gocloak.IntP(len(sessions))},
+		}, nil
+
+	case req.Input.ClientID != nil:
+		idOfClient, err := clientUUID(ctx, client, token, req.Input.RealmID, *req.Input.ClientID)
+		if err != nil {
+			return infer.FunctionResponse[LogoutSessionsResult]{}, err
+		}
+
+		// Keycloak has no endpoint to log out a client, so its sessions are collected first and deleted one by one
+		var sessionIDs []string
+		err = paginate(0, nil, pageSize, func(first, max int) (int, error) {
+			sessions, err := client.GetClientUserSessions(ctx, token, req.Input.RealmID, idOfClient, gocloak.GetClientUserSessionsParams{
+				First: gocloak.IntP(first),
+				Max:   gocloak.IntP(max),
+			})
+			if err != nil {
+				return 0, fmt.Errorf("failed to get sessions of client %s: %w", *req.Input.ClientID, err)
+			}
+			for _, session := range sessions {
+				sessionIDs = append(sessionIDs, gocloak.PString(session.ID))
+			}
+			return len(sessions), nil
+		})
+		if err != nil {
+			return infer.FunctionResponse[LogoutSessionsResult]{}, err
+		}
+
+		for _, sessionID := range sessionIDs {
+			resp, err := client.GetRequestWithBearerAuth(ctx, token).
+				Delete(adminRealmURL(ctx, req.Input.RealmID, "sessions", sessionID))
+			if err := checkResponse(resp, err); err != nil && !isNotFound(err) {
+				return infer.FunctionResponse[LogoutSessionsResult]{}, fmt.Errorf("failed to delete session %s: %w", sessionID, err)
+			}
+		}
+		return infer.FunctionResponse[LogoutSessionsResult]{
+			Output: LogoutSessionsResult{LoggedOutSessions: gocloak.IntP(len(sessionIDs))},
+		}, nil
+
+	default:
+		resp, err := client.GetRequestWithBearerAuth(ctx, token).
+			Post(adminRealmURL(ctx, req.Input.RealmID, "logout-all"))
+		if err := checkResponse(resp, err); err != nil {
+			return infer.FunctionResponse[LogoutSessionsResult]{}, fmt.Errorf("failed to log out all sessions of realm %s: %w", req.Input.RealmID, err)
+		}
+		return infer.FunctionResponse[LogoutSessionsResult]{}, nil
+	}
+}
@@ -0,0 +1,314 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// Organization represents a Keycloak organization (Keycloak 25+).
+// Attributes not listed in the inputs are preserved on update, so attributes
+// added through the admin console survive a deployment.
+type Organization struct{}
+
+type OrganizationArgs struct {
+	RealmID     string               `pulumi:"realmId" provider:"replaceOnChanges"`
+	Name        string               `pulumi:"name,optional"`
+	Alias       *string              `pulumi:"alias,optional" provider:"replaceOnChanges"`
+	Description *string              `pulumi:"description,optional"`
+	RedirectUrl *string              `pulumi:"redirectUrl,optional"`
+	Enabled     *bool                `pulumi:"enabled,optional"`
+	Domains     []OrganizationDomain `pulumi:"domains,optional"`
+	Attributes  map[string][]string  `pulumi:"attributes,optional"`
+}
+
+type OrganizationDomain struct {
+	Name     string `pulumi:"name"`
+	Verified *bool  `pulumi:"verified,optional"`
+}
+
+type OrganizationState struct {
+	OrganizationArgs
+	ID string `pulumi:"organizationId"`
+}
+
+// organizationRepresentation is the admin API wire format of an organization
+type organizationRepresentation struct {
+	ID          string                     `json:"id,omitempty"`
+	Name        string                     `json:"name"`
+	Alias       string                     `json:"alias,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	RedirectUrl string                     `json:"redirectUrl,omitempty"`
+	Enabled     bool                       `json:"enabled"`
+	Domains     []organizationDomainRecord `json:"domains,omitempty"`
+	Attributes  map[string][]string        `json:"attributes,omitempty"`
+}
+
+type organizationDomainRecord struct {
+	Name     string `json:"name"`
+	Verified bool   `json:"verified"`
+}
+
+// organizationsMinVersion is the first Keycloak major release shipping the organizations API
+const organizationsMinVersion = 25
+
+// Annotate provides schema documentation for the Organization resource
+func (o *Organization) Annotate(a infer.Annotator) {
+	a.Describe(&o, "A Keycloak organization. Requires Keycloak 25 or newer with organizations enabled on the realm")
+}
+
+func (args *OrganizationArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm the organization belongs to")
+	a.Describe(&args.Name, "The name of the organization, unique within the realm. Defaults to the resource name with a random suffix")
+	a.Describe(&args.Alias, "Unique alias of the organization, defaults to the name. Cannot be changed after creation")
+	a.Describe(&args.Description, "Description of the organization")
+	a.Describe(&args.RedirectUrl, "URL users are redirected to after completing registration or accepting an invitation")
+	a.Describe(&args.Enabled, "Whether the organization is enabled")
+	a.Describe(&args.Domains, "Internet domains owned by the organization")
+	a.Describe(&args.Attributes, "Custom attributes of the organization. Attributes not listed here are left untouched")
+
+	a.SetDefault(&args.Enabled, true)
+}
+
+func (d *OrganizationDomain) Annotate(a infer.Annotator) {
+	a.Describe(&d.Name, "The domain name, e.g. example.com")
+	a.Describe(&d.Verified, "Whether the domain ownership has been verified")
+
+	a.SetDefault(&d.Verified, false)
+}
+
+func (state *OrganizationState) Annotate(a infer.Annotator) {
+	a.Describe(&state.ID, "The unique identifier of the organization")
+}
+
+func (*Organization) Check(ctx context.Context, req infer.CheckRequest) (infer.CheckResponse[OrganizationArgs], error) {
+	inputs, err := withAutoName(req)
+	if err != nil {
+		return infer.CheckResponse[OrganizationArgs]{}, err
+	}
+
+	args, failures, err := infer.DefaultCheck[OrganizationArgs](ctx, inputs)
+	return infer.CheckResponse[OrganizationArgs]{
+		Inputs:   args,
+		Failures: failures,
+	}, err
+}
+
+func (o *Organization) Create(ctx context.Context, req infer.CreateRequest[OrganizationArgs]) (infer.CreateResponse[OrganizationState], error) {
+	if req.DryRun {
+		return infer.CreateResponse[OrganizationState]{
+			Output: OrganizationState{OrganizationArgs: req.Inputs},
+		}, nil
+	}
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.CreateResponse[OrganizationState]{}, err
+	}
+
+	if err := requireServerVersion(ctx, client, token, organizationsMinVersion, "organizations"); err != nil {
+		return infer.CreateResponse[OrganizationState]{}, err
+	}
+
+	org := req.Inputs.toRepresentation(organizationRepresentation{})
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetBody(org).
+		Post(adminRealmURL(ctx, req.Inputs.RealmID, "organizations"))
+	if err := checkResponse(resp, err); err != nil {
+		return infer.CreateResponse[OrganizationState]{}, fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	location := resp.Header().Get("Location")
+	if location == "" {
+		return infer.CreateResponse[OrganizationState]{}, fmt.Errorf("failed to create organization: Keycloak returned no Location header")
+	}
+	id := location[strings.LastIndex(location, "/")+1:]
+
+	state, err := readOrganizationState(ctx, req.Inputs.RealmID, id)
+	if err != nil {
+		return infer.CreateResponse[OrganizationState]{}, fmt.Errorf("failed to read organization state: %w", err)
+	}
+	state.Attributes = managedAttributes(state.Attributes, req.Inputs.Attributes)
+
+	// Same format as the import ID, see Read
+	return infer.CreateResponse[OrganizationState]{
+		ID:     req.Inputs.RealmID + "/" + id,
+		Output: state,
+	}, nil
+}
+
+func (o *Organization) Update(ctx context.Context, req infer.UpdateRequest[OrganizationArgs, OrganizationState]) (infer.UpdateResponse[OrganizationState], error) {
+	if req.DryRun {
+		return infer.UpdateResponse[OrganizationState]{
+			Output: OrganizationState{OrganizationArgs: req.Inputs, ID: req.State.ID},
+		}, nil
+	}
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.UpdateResponse[OrganizationState]{}, err
+	}
+
+	var current organizationRepresentation
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetResult(&current).
+		Get(adminRealmURL(ctx, req.Inputs.RealmID, "organizations", req.State.ID))
+	if err := checkResponse(resp, err); err != nil {
+		return infer.UpdateResponse[OrganizationState]{}, fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	// Drop attributes that were previously managed but have been removed from the inputs
+	for key := range req.State.Attributes {
+		if _, ok := req.Inputs.Attributes[key]; !ok {
+			delete(current.Attributes, key)
+		}
+	}
+
+	org := req.Inputs.toRepresentation(current)
+	resp, err = client.GetRequestWithBearerAuth(ctx, token).
+		SetBody(org).
+		Put(adminRealmURL(ctx, req.Inputs.RealmID, "organizations", req.State.ID))
+	if err := checkResponse(resp, err); err != nil {
+		return infer.UpdateResponse[OrganizationState]{}, fmt.Errorf("failed to update organization: %w", err)
+	}
+
+	state, err := readOrganizationState(ctx, req.Inputs.RealmID, req.State.ID)
+	if err != nil {
+		return infer.UpdateResponse[OrganizationState]{}, fmt.Errorf("failed to read organization state: %w", err)
+	}
+	// Attributes added through the admin console stay out of the state, so that the next update leaves them alone
+	state.Attributes = managedAttributes(state.Attributes, req.Inputs.Attributes)
+
+	return infer.UpdateResponse[OrganizationState]{
+		Output: state,
+	}, nil
+}
+
+func (o *Organization) Delete(ctx context.Context, req infer.DeleteRequest[OrganizationState]) (infer.DeleteResponse, error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		Delete(adminRealmURL(ctx, req.State.RealmID, "organizations", req.State.ID))
+	if err := checkResponse(resp, err); err != nil && !isNotFound(err) {
+		return infer.DeleteResponse{}, fmt.Errorf("failed to delete organization: %w", err)
+	}
+
+	return infer.DeleteResponse{}, nil
+}
+
+func (o *Organization) Read(ctx context.Context, req infer.ReadRequest[OrganizationArgs, OrganizationState]) (infer.ReadResponse[OrganizationArgs, OrganizationState], error) {
+	// Organizations are imported with an ID of the form <realm>/<organizationId>
+	realmName, id := req.State.RealmID, req.ID
+	if strings.Contains(req.ID, "/") {
+		parts, err := parseResourceID(req.ID, "<realm>/<organizationId>")
+		if err != nil {
+			return infer.ReadResponse[OrganizationArgs, OrganizationState]{}, err
+		}
+		realmName, id = parts[0], parts[1]
+	}
+
+	state, err := readOrganizationState(ctx, realmName, id)
+	if err != nil {
+		if isNotFound(err) {
+			return infer.ReadResponse[OrganizationArgs, OrganizationState]{}, nil
+		}
+		return infer.ReadResponse[OrganizationArgs, OrganizationState]{}, fmt.Errorf("failed to read organization state: %w", err)
+	}
+
+	// Only report the attributes this resource manages, which for an import are none
+	state.Attributes = managedAttributes(state.Attributes, req.Inputs.Attributes)
+
+	return infer.ReadResponse[OrganizationArgs, OrganizationState]{
+		ID:     req.ID,
+		Inputs: state.OrganizationArgs,
+		State:  state,
+	}, nil
+}
+
+// toRepresentation overlays the managed fields onto an existing organization representation
+func (args OrganizationArgs) toRepresentation(org organizationRepresentation) organizationRepresentation {
+	org.Name = args.Name
+	if args.Alias != nil {
+		org.Alias = *args.Alias
+	}
+	if args.Description != nil {
+		org.Description = *args.Description
+	}
+	if args.RedirectUrl != nil {
+		org.RedirectUrl = *args.RedirectUrl
+	}
+
+	org.Enabled = true
+	if args.Enabled != nil {
+		org.Enabled = *args.Enabled
+	}
+
+	org.Domains = make([]organizationDomainRecord, 0, len(args.Domains))
+	for _, domain := range args.Domains {
+		record := organizationDomainRecord{Name: domain.Name}
+		if domain.Verified != nil {
+			record.Verified = *domain.Verified
+		}
+		org.Domains = append(org.Domains, record)
+	}
+
+	if len(args.Attributes) > 0 && org.Attributes == nil {
+		org.Attributes = make(map[string][]string)
+	}
+	for key, value := range args.Attributes {
+		org.Attributes[key] = value
+	}
+
+	return org
+}
+
+func readOrganizationState(ctx context.Context, realmName, id string) (OrganizationState, error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return OrganizationState{}, err
+	}
+
+	var org organizationRepresentation
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetResult(&org).
+		Get(adminRealmURL(ctx, realmName, "organizations", id))
+	if err := checkResponse(resp, err); err != nil {
+		return OrganizationState{}, err
+	}
+
+	enabled := org.Enabled
+	state := OrganizationState{
+		OrganizationArgs: OrganizationArgs{
+			RealmID:    realmName,
+			Name:       org.Name,
+			Enabled:    &enabled,
+			Attributes: org.Attributes,
+		},
+		ID: org.ID,
+	}
+
+	if org.Alias != "" {
+		state.Alias = &org.Alias
+	}
+	if org.Description != "" {
+		state.Description = &org.Description
+	}
+	if org.RedirectUrl != "" {
+		state.RedirectUrl = &org.RedirectUrl
+	}
+
+	for _, domain := range org.Domains {
+		verified := domain.Verified
+		state.Domains = append(state.Domains, OrganizationDomain{
+			Name:     domain.Name,
+			Verified: &verified,
+		})
+	}
+
+	return state, nil
+}
@@ -0,0 +1,232 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// OrganizationIdentityProvider links an existing identity provider to an organization
+// and configures the domain used for home identity provider discovery.
+type OrganizationIdentityProvider struct{}
+
+type OrganizationIdentityProviderArgs struct {
+	RealmID                  string  `pulumi:"realmId" provider:"replaceOnChanges"`
+	OrganizationID           string  `pulumi:"organizationId" provider:"replaceOnChanges"`
+	IdentityProviderAlias    string  `pulumi:"identityProviderAlias" provider:"replaceOnChanges"`
+	Domain                   *string `pulumi:"domain,optional"`
+	RedirectWhenEmailMatches *bool   `pulumi:"redirectWhenEmailMatches,optional"`
+}
+
+type OrganizationIdentityProviderState struct {
+	OrganizationIdentityProviderArgs
+}
+
+// Identity provider config keys used by Keycloak for organization routing
+const (
+	organizationDomainConfigKey       = "kc.org.domain"
+	organizationRedirectModeConfigKey = "kc.org.broker.redirect.mode.email-matches"
+)
+
+// Annotate provides schema documentation for the OrganizationIdentityProvider resource
+func (o *OrganizationIdentityProvider) Annotate(a infer.Annotator) {
+	a.Describe(&o, "Links an identity provider to a Keycloak organization, enabling domain-based home identity provider discovery")
+}
+
+func (args *OrganizationIdentityProviderArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm the organization belongs to")
+	a.Describe(&args.OrganizationID, "The ID of the organization")
+	a.Describe(&args.IdentityProviderAlias, "The alias of the identity provider to link")
+	a.Describe(&args.Domain, "Organization domain routed to this identity provider")
+	a.Describe(&args.RedirectWhenEmailMatches, "Whether users are redirected to this identity provider when their email matches the domain")
+}
+
+func (o *OrganizationIdentityProvider) Create(ctx context.Context, req infer.CreateRequest[OrganizationIdentityProviderArgs]) (infer.CreateResponse[OrganizationIdentityProviderState], error) {
+	id := req.Inputs.RealmID + "/" + req.Inputs.OrganizationID + "/" + req.Inputs.IdentityProviderAlias
+
+	if req.DryRun {
+		return infer.CreateResponse[OrganizationIdentityProviderState]{
+			ID:     id,
+			Output: OrganizationIdentityProviderState{req.Inputs},
+		}, nil
+	}
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.CreateResponse[OrganizationIdentityProviderState]{}, err
+	}
+
+	if err := requireServerVersion(ctx, client, token, organizationsMinVersion, "organizations"); err != nil {
+		return infer.CreateResponse[OrganizationIdentityProviderState]{}, err
+	}
+
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetBody(req.Inputs.IdentityProviderAlias).
+		Post(adminRealmURL(ctx, req.Inputs.RealmID, "organizations", req.Inputs.OrganizationID, "identity-providers"))
+	if err := checkResponse(resp, err); err != nil {
+		return infer.CreateResponse[OrganizationIdentityProviderState]{}, fmt.Errorf("failed to link identity provider to organization: %w", err)
+	}
+
+	if err := updateOrganizationRouting(ctx, client, token, req.Inputs, OrganizationIdentityProviderArgs{}); err != nil {
+		return infer.CreateResponse[OrganizationIdentityProviderState]{}, err
+	}
+
+	state, err := readOrganizationIdentityProviderState(ctx, client, token, req.Inputs)
+	if err != nil {
+		return infer.CreateResponse[OrganizationIdentityProviderState]{}, fmt.Errorf("failed to read organization identity provider state: %w", err)
+	}
+
+	return infer.CreateResponse[OrganizationIdentityProviderState]{
+		ID:     id,
+		Output: state,
+	}, nil
+}
+
+func (o *OrganizationIdentityProvider) Update(ctx context.Context, req infer.UpdateRequest[OrganizationIdentityProviderArgs, OrganizationIdentityProviderState]) (infer.UpdateResponse[OrganizationIdentityProviderState], error) {
+	if req.DryRun {
+		return infer.UpdateResponse[OrganizationIdentityProviderState]{
+			Output: OrganizationIdentityProviderState{req.Inputs},
+		}, nil
+	}
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.UpdateResponse[OrganizationIdentityProviderState]{}, err
+	}
+
+	if err := updateOrganizationRouting(ctx, client, token, req.Inputs, req.State.OrganizationIdentityProviderArgs); err != nil {
+		return infer.UpdateResponse[OrganizationIdentityProviderState]{}, err
+	}
+
+	state, err := readOrganizationIdentityProviderState(ctx, client, token, req.Inputs)
+	if err != nil {
+		return infer.UpdateResponse[OrganizationIdentityProviderState]{}, fmt.Errorf("failed to read organization identity provider state: %w", err)
+	}
+
+	return infer.UpdateResponse[OrganizationIdentityProviderState]{
+		Output: state,
+	}, nil
+}
+
+func (o *OrganizationIdentityProvider) Delete(ctx context.Context, req infer.DeleteRequest[OrganizationIdentityProviderState]) (infer.DeleteResponse, error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		Delete(adminRealmURL(ctx, req.State.RealmID, "organizations", req.State.OrganizationID, "identity-providers", req.State.IdentityProviderAlias))
+	if err := checkResponse(resp, err); err != nil && !isNotFound(err) {
+		return infer.DeleteResponse{}, fmt.Errorf("failed to unlink identity provider from organization: %w", err)
+	}
+
+	return infer.DeleteResponse{}, nil
+}
+
+func (o *OrganizationIdentityProvider) Read(ctx context.Context, req infer.ReadRequest[OrganizationIdentityProviderArgs, OrganizationIdentityProviderState]) (infer.ReadResponse[OrganizationIdentityProviderArgs, OrganizationIdentityProviderState], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.ReadResponse[OrganizationIdentityProviderArgs, OrganizationIdentityProviderState]{}, err
+	}
+
+	parts, err := parseResourceID(req.ID, "<realm>/<organizationId>/<identityProviderAlias>")
+	if err != nil {
+		return infer.ReadResponse[OrganizationIdentityProviderArgs, OrganizationIdentityProviderState]{}, err
+	}
+	args := req.State.OrganizationIdentityProviderArgs
+	args.RealmID, args.OrganizationID, args.IdentityProviderAlias = parts[0], parts[1], parts[2]
+
+	state, err := readOrganizationIdentityProviderState(ctx, client, token, args)
+	if err != nil {
+		if isNotFound(err) {
+			return infer.ReadResponse[OrganizationIdentityProviderArgs, OrganizationIdentityProviderState]{}, nil
+		}
+		return infer.ReadResponse[OrganizationIdentityProviderArgs, OrganizationIdentityProviderState]{}, fmt.Errorf("failed to read organization identity provider state: %w", err)
+	}
+
+	return infer.ReadResponse[OrganizationIdentityProviderArgs, OrganizationIdentityProviderState]{
+		ID:     req.ID,
+		Inputs: state.OrganizationIdentityProviderArgs,
+		State:  state,
+	}, nil
+}
+
+// updateOrganizationRouting writes the routing settings into the identity provider config, and removes the ones
+// previously set that are no longer.
+// The identity provider is handled as a raw map so fields gocloak does not model
+// (such as organizationId) survive the round trip.
+func updateOrganizationRouting(ctx context.Context, client KeycloakClient, token string, args, previous OrganizationIdentityProviderArgs) error {
+	removeDomain := args.Domain == nil && previous.Domain != nil
+	removeRedirect := args.RedirectWhenEmailMatches == nil && previous.RedirectWhenEmailMatches != nil
+	if args.Domain == nil && args.RedirectWhenEmailMatches == nil && !removeDomain && !removeRedirect {
+		return nil
+	}
+
+	url := adminRealmURL(ctx, args.RealmID, "identity-provider", "instances", args.IdentityProviderAlias)
+
+	var idp map[string]interface{}
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetResult(&idp).
+		Get(url)
+	if err := checkResponse(resp, err); err != nil {
+		return fmt.Errorf("failed to get identity provider: %w", err)
+	}
+
+	config, _ := idp["config"].(map[string]interface{})
+	if config == nil {
+		config = make(map[string]interface{})
+	}
+	if args.Domain != nil {
+		config[organizationDomainConfigKey] = *args.Domain
+	}
+	if args.RedirectWhenEmailMatches != nil {
+		config[organizationRedirectModeConfigKey] = fmt.Sprintf("%t", *args.RedirectWhenEmailMatches)
+	}
+	if removeDomain {
+		delete(config, organizationDomainConfigKey)
+	}
+	if removeRedirect {
+		delete(config, organizationRedirectModeConfigKey)
+	}
+	idp["config"] = config
+
+	resp, err = client.GetRequestWithBearerAuth(ctx, token).
+		SetBody(idp).
+		Put(url)
+	if err := checkResponse(resp, err); err != nil {
+		return fmt.Errorf("failed to update identity provider routing: %w", err)
+	}
+
+	return nil
+}
+
+func readOrganizationIdentityProviderState(ctx context.Context, client KeycloakClient, token string, args OrganizationIdentityProviderArgs) (OrganizationIdentityProviderState, error) {
+	var idp struct {
+		Alias  string            `json:"alias"`
+		Config map[string]string `json:"config"`
+	}
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetResult(&idp).
+		Get(adminRealmURL(ctx, args.RealmID, "organizations", args.OrganizationID, "identity-providers", args.IdentityProviderAlias))
+	if err := checkResponse(resp, err); err != nil {
+		return OrganizationIdentityProviderState{}, err
+	}
+
+	state := OrganizationIdentityProviderState{
+		OrganizationIdentityProviderArgs{
+			RealmID:               args.RealmID,
+			OrganizationID:        args.OrganizationID,
+			IdentityProviderAlias: idp.Alias,
+		},
+	}
+	if domain, ok := idp.Config[organizationDomainConfigKey]; ok {
+		state.Domain = &domain
+	}
+	if redirect, ok := idp.Config[organizationRedirectModeConfigKey]; ok {
+		redirectBool := redirect == "true"
+		state.RedirectWhenEmailMatches = &redirectBool
+	}
+
+	return state, nil
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/property"
+)
+
+func TestOrganizationIdentityProviderRemovesRoutingDroppedFromInputs(t *testing.T) {
+	mux := http.NewServeMux()
+	var mu sync.Mutex
+	idp := map[string]any{"alias": "google", "config": map[string]any{"clientId": "google-client"}}
+	mux.HandleFunc("POST /admin/realms/acme/organizations/org-1/identity-providers", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	serveIdentityProvider := func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		writeJSON(w, http.StatusOK, idp)
+	}
+	mux.HandleFunc("GET /admin/realms/acme/organizations/org-1/identity-providers/google", serveIdentityProvider)
+	mux.HandleFunc("GET /admin/realms/acme/identity-provider/instances/google", serveIdentityProvider)
+	mux.HandleFunc("PUT /admin/realms/acme/identity-provider/instances/google", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err := json.NewDecoder(r.Body).Decode(&idp); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	server := newAdminAPIServer(t, mux)
+
+	urn := testURN("OrganizationIdentityProvider", "google")
+	inputs := func(routing map[string]property.Value) property.Map {
+		routing["realmId"] = property.New("acme")
+		routing["organizationId"] = property.New("org-1")
+		routing["identityProviderAlias"] = property.New("google")
+		return property.NewMap(routing)
+	}
+	created, err := server.Create(p.CreateRequest{Urn: urn, Properties: inputs(map[string]property.Value{
+		"domain":                   property.New("acme.com"),
+		"redirectWhenEmailMatches": property.New(true),
+	})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ensureEqual(t, "domain", "acme.com", stringProperty(t, created.Properties, "domain"))
+
+	updated, err := server.Update(p.UpdateRequest{ID: created.ID, Urn: urn, State: created.Properties, Inputs: inputs(map[string]property.Value{})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"domain", "redirectWhenEmailMatches"} {
+		if value, ok := updated.Properties.GetOk(key); ok {
+			t.Errorf("%s is still set after its removal from the inputs: %v", key, value)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	config := idp["config"].(map[string]any)
+	ensureEqual(t, "config", 1, len(config))
+	ensureEqual(t, "clientId", "google-client", config["clientId"])
+}
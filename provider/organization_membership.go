@@ -0,0 +1,240 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// OrganizationMembership manages a set of users belonging to an organization.
+// Membership is additive: members added outside of Pulumi are never removed,
+// only users previously added by this resource are.
+type OrganizationMembership struct{}
+
+type OrganizationMembershipArgs struct {
+	RealmID        string   `pulumi:"realmId" provider:"replaceOnChanges"`
+	OrganizationID string   `pulumi:"organizationId" provider:"replaceOnChanges"`
+	UserIDs        []string `pulumi:"userIds"`
+}
+
+type OrganizationMembershipState struct {
+	OrganizationMembershipArgs
+	MembershipTypes map[string]string `pulumi:"membershipTypes"`
+}
+
+// organizationMemberRepresentation is the admin API wire format of an organization member
+type organizationMemberRepresentation struct {
+	ID             string `json:"id"`
+	Username       string `json:"username"`
+	MembershipType string `json:"membershipType"`
+}
+
+// Annotate provides schema documentation for the OrganizationMembership resource
+func (m *OrganizationMembership) Annotate(a infer.Annotator) {
+	a.Describe(&m, "Users belonging to a Keycloak organization. Members managed elsewhere are preserved")
+}
+
+func (args *OrganizationMembershipArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm the organization belongs to")
+	a.Describe(&args.OrganizationID, "The ID of the organization")
+	a.Describe(&args.UserIDs, "IDs of the users that should be members of the organization")
+}
+
+func (state *OrganizationMembershipState) Annotate(a infer.Annotator) {
+	a.Describe(&state.MembershipTypes, "Membership type (MANAGED or UNMANAGED) of each managed member, keyed by user ID. Keycloak derives it from how the user joined, so it cannot be set")
+}
+
+func (m *OrganizationMembership) Create(ctx context.Context, req infer.CreateRequest[OrganizationMembershipArgs]) (infer.CreateResponse[OrganizationMembershipState], error) {
+	id := req.Inputs.RealmID + "/" + req.Inputs.OrganizationID
+
+	if req.DryRun {
+		return infer.CreateResponse[OrganizationMembershipState]{
+			ID:     id,
+			Output: OrganizationMembershipState{OrganizationMembershipArgs: req.Inputs},
+		}, nil
+	}
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.CreateResponse[OrganizationMembershipState]{}, err
+	}
+
+	if err := requireServerVersion(ctx, client, token, organizationsMinVersion, "organizations"); err != nil {
+		return infer.CreateResponse[OrganizationMembershipState]{}, err
+	}
+
+	state, err := syncOrganizationMembers(ctx, client, token, req.Inputs, nil)
+	if err != nil {
+		return infer.CreateResponse[OrganizationMembershipState]{}, err
+	}
+
+	return infer.CreateResponse[OrganizationMembershipState]{
+		ID:     id,
+		Output: state,
+	}, nil
+}
+
+func (m *OrganizationMembership) Update(ctx context.Context, req infer.UpdateRequest[OrganizationMembershipArgs, OrganizationMembershipState]) (infer.UpdateResponse[OrganizationMembershipState], error) {
+	if req.DryRun {
+		return infer.UpdateResponse[OrganizationMembershipState]{
+			Output: OrganizationMembershipState{OrganizationMembershipArgs: req.Inputs},
+		}, nil
+	}
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.UpdateResponse[OrganizationMembershipState]{}, err
+	}
+
+	state, err := syncOrganizationMembers(ctx, client, token, req.Inputs, req.State.UserIDs)
+	if err != nil {
+		return infer.UpdateResponse[OrganizationMembershipState]{}, err
+	}
+
+	return infer.UpdateResponse[OrganizationMembershipState]{
+		Output: state,
+	}, nil
+}
+
+func (m *OrganizationMembership) Delete(ctx context.Context, req infer.DeleteRequest[OrganizationMembershipState]) (infer.DeleteResponse, error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
+	for _, userID := range req.State.UserIDs {
+		resp, err := client.GetRequestWithBearerAuth(ctx, token).
+			Delete(adminRealmURL(ctx, req.State.RealmID, "organizations", req.State.OrganizationID, "members", userID))
+		if err := checkResponse(resp, err); err != nil && !isNotFound(err) {
+			return infer.DeleteResponse{}, fmt.Errorf("failed to remove user %s from organization: %w", userID, err)
+		}
+	}
+
+	return infer.DeleteResponse{}, nil
+}
+
+func (m *OrganizationMembership) Read(ctx context.Context, req infer.ReadRequest[OrganizationMembershipArgs, OrganizationMembershipState]) (infer.ReadResponse[OrganizationMembershipArgs, OrganizationMembershipState], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.ReadResponse[OrganizationMembershipArgs, OrganizationMembershipState]{}, err
+	}
+
+	parts, err := parseResourceID(req.ID, "<realm>/<organizationId>")
+	if err != nil {
+		return infer.ReadResponse[OrganizationMembershipArgs, OrganizationMembershipState]{}, err
+	}
+	realmName, organizationID := parts[0], parts[1]
+
+	members, err := listOrganizationMembers(ctx, client, token, realmName, organizationID)
+	if err != nil {
+		if isNotFound(err) {
+			return infer.ReadResponse[OrganizationMembershipArgs, OrganizationMembershipState]{}, nil
+		}
+		return infer.ReadResponse[OrganizationMembershipArgs, OrganizationMembershipState]{}, err
+	}
+
+	// An import manages all current members, otherwise only the managed ones are refreshed
+	userIDs := req.State.UserIDs
+	if req.State.RealmID == "" {
+		userIDs = slices.Sorted(maps.Keys(members))
+	}
+
+	state := OrganizationMembershipState{
+		OrganizationMembershipArgs: OrganizationMembershipArgs{
+			RealmID:        realmName,
+			OrganizationID: organizationID,
+			UserIDs:        []string{},
+		},
+		MembershipTypes: map[string]string{},
+	}
+	for _, userID := range userIDs {
+		if member, ok := members[userID]; ok {
+			state.UserIDs = append(state.UserIDs, userID)
+			state.MembershipTypes[userID] = member.MembershipType
+		}
+	}
+
+	return infer.ReadResponse[OrganizationMembershipArgs, OrganizationMembershipState]{
+		ID:     req.ID,
+		Inputs: state.OrganizationMembershipArgs,
+		State:  state,
+	}, nil
+}
+
+// syncOrganizationMembers adds missing members and removes users that were previously managed but are no longer listed
+func syncOrganizationMembers(ctx context.Context, client KeycloakClient, token string, args OrganizationMembershipArgs, previous []string) (OrganizationMembershipState, error) {
+	members, err := listOrganizationMembers(ctx, client, token, args.RealmID, args.OrganizationID)
+	if err != nil {
+		return OrganizationMembershipState{}, err
+	}
+
+	wanted := make(map[string]bool, len(args.UserIDs))
+	for _, userID := range args.UserIDs {
+		wanted[userID] = true
+		if _, ok := members[userID]; ok {
+			continue
+		}
+		resp, err := client.GetRequestWithBearerAuth(ctx, token).
+			SetBody(userID).
+			Post(adminRealmURL(ctx, args.RealmID, "organizations", args.OrganizationID, "members"))
+		if err := checkResponse(resp, err); err != nil {
+			return OrganizationMembershipState{}, fmt.Errorf("failed to add user %s to organization: %w", userID, err)
+		}
+	}
+
+	for _, userID := range previous {
+		if wanted[userID] {
+			continue
+		}
+		resp, err := client.GetRequestWithBearerAuth(ctx, token).
+			Delete(adminRealmURL(ctx, args.RealmID, "organizations", args.OrganizationID, "members", userID))
+		if err := checkResponse(resp, err); err != nil && !isNotFound(err) {
+			return OrganizationMembershipState{}, fmt.Errorf("failed to remove user %s from organization: %w", userID, err)
+		}
+	}
+
+	members, err = listOrganizationMembers(ctx, client, token, args.RealmID, args.OrganizationID)
+	if err != nil {
+		return OrganizationMembershipState{}, err
+	}
+
+	state := OrganizationMembershipState{
+		OrganizationMembershipArgs: args,
+		MembershipTypes:            map[string]string{},
+	}
+	for _, userID := range args.UserIDs {
+		if member, ok := members[userID]; ok {
+			state.MembershipTypes[userID] = member.MembershipType
+		}
+	}
+
+	return state, nil
+}
+
+// listOrganizationMembers returns all members of an organization keyed by user ID
+func listOrganizationMembers(ctx context.Context, client KeycloakClient, token, realmName, organizationID string) (map[string]organizationMemberRepresentation, error) {
+	members := make(map[string]organizationMemberRepresentation)
+	err := paginate(0, nil, pageSize, func(first, max int) (int, error) {
+		var page []organizationMemberRepresentation
+		resp, err := client.GetRequestWithBearerAuth(ctx, token).
+			SetQueryParam("first", strconv.Itoa(first)).
+			SetQueryParam("max", strconv.Itoa(max)).
+			SetResult(&page).
+			Get(adminRealmURL(ctx, realmName, "organizations", organizationID, "members"))
+		if err := checkResponse(resp, err); err != nil {
+			return 0, fmt.Errorf("failed to list organization members: %w", err)
+		}
+		for _, member := range page {
+			members[member.ID] = member
+		}
+		return len(page), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/property"
+)
+
+// fakeOrganizations serves the organizations of one realm
+type fakeOrganizations struct {
+	mu            sync.Mutex
+	organizations map[string]organizationRepresentation
+}
+
+func newFakeOrganizations(mux *http.ServeMux) *fakeOrganizations {
+	fake := &fakeOrganizations{organizations: map[string]organizationRepresentation{}}
+	mux.HandleFunc("POST /admin/realms/acme/organizations", func(w http.ResponseWriter, r *http.Request) {
+		var org organizationRepresentation
+		if err := json.NewDecoder(r.Body).Decode(&org); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		org.ID = "org-1"
+		fake.organizations[org.ID] = org
+		w.Header().Set("Location", "http://"+r.Host+r.URL.Path+"/"+org.ID)
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("GET /admin/realms/acme/organizations/{id}", func(w http.ResponseWriter, r *http.Request) {
+		org, ok := fake.get(r.PathValue("id"))
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, http.StatusOK, org)
+	})
+	mux.HandleFunc("PUT /admin/realms/acme/organizations/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var org organizationRepresentation
+		if err := json.NewDecoder(r.Body).Decode(&org); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fake.put(r.PathValue("id"), org)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return fake
+}
+
+func (f *fakeOrganizations) get(id string) (organizationRepresentation, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	org, ok := f.organizations[id]
+	return org, ok
+}
+
+func (f *fakeOrganizations) put(id string, org organizationRepresentation) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	org.ID = id
+	f.organizations[id] = org
+}
+
+func TestOrganizationLeavesUnlistedAttributesAlone(t *testing.T) {
+	mux := http.NewServeMux()
+	fake := newFakeOrganizations(mux)
+	server := newAdminAPIServer(t, mux)
+
+	urn := testURN("Organization", "acme")
+	inputs := func(tier string) property.Map {
+		return property.NewMap(map[string]property.Value{
+			"realmId": property.New("acme"),
+			"name":    property.New("Acme"),
+			"attributes": property.New(map[string]property.Value{
+				"tier": property.New([]property.Value{property.New(tier)}),
+			}),
+		})
+	}
+	created, err := server.Create(p.CreateRequest{Urn: urn, Properties: inputs("gold")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ensureEqual(t, "id", "acme/org-1", created.ID)
+
+	// An attribute added in the admin console
+	org, _ := fake.get("org-1")
+	org.Attributes["region"] = []string{"eu"}
+	fake.put("org-1", org)
+
+	updated, err := server.Update(p.UpdateRequest{ID: created.ID, Urn: urn, State: created.Properties, Inputs: inputs("platinum")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	org, _ = fake.get("org-1")
+	ensureEqual(t, "tier", "platinum", org.Attributes["tier"][0])
+	if _, ok := org.Attributes["region"]; !ok {
+		t.Error("an update removed an attribute added in the admin console")
+	}
+	if _, ok := updated.Properties.Get("attributes").AsMap().GetOk("region"); ok {
+		t.Error("the state records an attribute the inputs do not manage")
+	}
+
+	// A second update must not take the console attribute for one removed from the inputs
+	if _, err := server.Update(p.UpdateRequest{ID: created.ID, Urn: urn, State: updated.Properties, Inputs: inputs("gold")}); err != nil {
+		t.Fatal(err)
+	}
+	if org, _ := fake.get("org-1"); org.Attributes["region"] == nil {
+		t.Error("a second update removed an attribute added in the admin console")
+	}
+
+	imported, err := server.Read(p.ReadRequest{ID: created.ID, Urn: urn})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attributes, ok := imported.Inputs.GetOk("attributes"); ok {
+		t.Errorf("an import manages the live attributes: %v", attributes)
+	}
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// Ping checks that the provider configuration works against the Keycloak server
+type Ping struct{}
+
+type PingArgs struct{}
+
+type PingResult struct {
+	Url           string   `pulumi:"url"`
+	ServerVersion string   `pulumi:"serverVersion"`
+	VisibleRealms []string `pulumi:"visibleRealms"`
+}
+
+// Annotate provides schema documentation for the ping function
+func (p *Ping) Annotate(a infer.Annotator) {
+	a.Describe(&p, "Checks that the configured URL is reachable, the credentials are valid and the account has admin access. "+
+		"Fails with a descriptive error otherwise")
+}
+
+func (result *PingResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.Url, "The Keycloak URL the provider is configured with, including basePath")
+	a.Describe(&result.ServerVersion, "The version reported by the Keycloak server")
+	a.Describe(&result.VisibleRealms, "Names of the realms the provider can see, sorted")
+}
+
+func (p *Ping) Invoke(ctx context.Context, req infer.FunctionRequest[PingArgs]) (infer.FunctionResponse[PingResult], error) {
+	config := infer.GetConfig[ProviderConfig](ctx)
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[PingResult]{}, fmt.Errorf("cannot log in to %s: %w", config.baseURL(), err)
+	}
+
+	version, err := serverVersion(ctx, client, token)
+	if err != nil {
+		return infer.FunctionResponse[PingResult]{}, fmt.Errorf("logged in to %s but cannot read server info, the account may lack admin permissions: %w", config.baseURL(), err)
+	}
+
+	realms, err := client.GetRealms(ctx, token)
+	if err != nil {
+		return infer.FunctionResponse[PingResult]{}, fmt.Errorf("logged in to %s but cannot list realms, the account may lack admin permissions: %w", config.baseURL(), err)
+	}
+
+	result := PingResult{
+		Url:           config.baseURL(),
+		ServerVersion: version,
+		VisibleRealms: []string{},
+	}
+	for _, realm := range realms {
+		result.VisibleRealms = append(result.VisibleRealms, gocloak.PString(realm.Realm))
+	}
+	sort.Strings(result.VisibleRealms)
+
+	return infer.FunctionResponse[PingResult]{
+		Output: result,
+	}, nil
+}
@@ -0,0 +1,267 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// ProtocolMapper manages a protocol mapper attached either to a client or to a client scope.
+// Attaching mappers to a shared client scope lets many clients pick up the same claims.
+type ProtocolMapper struct{}
+
+type ProtocolMapperArgs struct {
+	RealmID         string            `pulumi:"realmId" provider:"replaceOnChanges"`
+	ClientID        *string           `pulumi:"clientId,optional" provider:"replaceOnChanges"`
+	ClientScopeID   *string           `pulumi:"clientScopeId,optional" provider:"replaceOnChanges"`
+	Name            string            `pulumi:"name,optional"`
+	Protocol        *string           `pulumi:"protocol,optional" provider:"replaceOnChanges"`
+	ProtocolMapper  string            `pulumi:"protocolMapper" provider:"replaceOnChanges"`
+	ConsentRequired *bool             `pulumi:"consentRequired,optional"`
+	Config          map[string]string `pulumi:"config,optional"`
+}
+
+type ProtocolMapperState struct {
+	ProtocolMapperArgs
+	ID string `pulumi:"mapperId"`
+}
+
+// Annotate provides schema documentation for the ProtocolMapper resource
+func (m *ProtocolMapper) Annotate(a infer.Annotator) {
+	a.Describe(&m, "A protocol mapper attached to a Keycloak client or client scope")
+}
+
+func (args *ProtocolMapperArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.ClientID, "The internal ID of the client the mapper is attached to. Exactly one of clientId or clientScopeId must be set")
+	a.Describe(&args.ClientScopeID, "The ID of the client scope the mapper is attached to. Exactly one of clientId or clientScopeId must be set")
+	a.Describe(&args.Name, "The name of the mapper, unique within the client or client scope. Defaults to the resource name with a random suffix")
+	a.Describe(&args.Protocol, "The protocol of the mapper: openid-connect or saml")
+	a.Describe(&args.ProtocolMapper, "The mapper type, e.g. oidc-usermodel-attribute-mapper")
+	a.Describe(&args.ConsentRequired, "Whether user consent is required for the mapper")
+	a.Describe(&args.Config, "Mapper specific configuration")
+
+	a.SetDefault(&args.Protocol, "openid-connect")
+}
+
+func (state *ProtocolMapperState) Annotate(a infer.Annotator) {
+	a.Describe(&state.ID, "The unique identifier of the mapper")
+}
+
+func (*ProtocolMapper) Check(ctx context.Context, req infer.CheckRequest) (infer.CheckResponse[ProtocolMapperArgs], error) {
+	inputs, err := withAutoName(req)
+	if err != nil {
+		return infer.CheckResponse[ProtocolMapperArgs]{}, err
+	}
+
+	args, failures, err := infer.DefaultCheck[ProtocolMapperArgs](ctx, inputs)
+	if err != nil {
+		return infer.CheckResponse[ProtocolMapperArgs]{Inputs: args, Failures: failures}, err
+	}
+
+	if (args.ClientID == nil) == (args.ClientScopeID == nil) {
+		failures = append(failures, p.CheckFailure{
+			Property: "clientId",
+			Reason:   "exactly one of clientId or clientScopeId must be set",
+		})
+	}
+
+	return infer.CheckResponse[ProtocolMapperArgs]{
+		Inputs:   args,
+		Failures: failures,
+	}, nil
+}
+
+func (m *ProtocolMapper) Create(ctx context.Context, req infer.CreateRequest[ProtocolMapperArgs]) (infer.CreateResponse[ProtocolMapperState], error) {
+	if req.DryRun {
+		return infer.CreateResponse[ProtocolMapperState]{
+			Output: ProtocolMapperState{ProtocolMapperArgs: req.Inputs},
+		}, nil
+	}
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.CreateResponse[ProtocolMapperState]{}, err
+	}
+
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetBody(req.Inputs.toRepresentation()).
+		Post(req.Inputs.mappersURL(ctx))
+	if err := checkResponse(resp, err); err != nil {
+		return infer.CreateResponse[ProtocolMapperState]{}, fmt.Errorf("failed to create protocol mapper: %w", err)
+	}
+
+	location := resp.Header().Get("Location")
+	if location == "" {
+		return infer.CreateResponse[ProtocolMapperState]{}, fmt.Errorf("failed to create protocol mapper: Keycloak returned no Location header")
+	}
+	id := location[strings.LastIndex(location, "/")+1:]
+
+	state, err := readProtocolMapperState(ctx, client, token, req.Inputs, id)
+	if err != nil {
+		return infer.CreateResponse[ProtocolMapperState]{}, fmt.Errorf("failed to read protocol mapper state: %w", err)
+	}
+
+	// Same format as the import ID, see Read
+	return infer.CreateResponse[ProtocolMapperState]{
+		ID:     req.Inputs.importID(id),
+		Output: state,
+	}, nil
+}
+
+func (m *ProtocolMapper) Update(ctx context.Context, req infer.UpdateRequest[ProtocolMapperArgs, ProtocolMapperState]) (infer.UpdateResponse[ProtocolMapperState], error) {
+	if req.DryRun {
+		return infer.UpdateResponse[ProtocolMapperState]{
+			Output: ProtocolMapperState{ProtocolMapperArgs: req.Inputs, ID: req.State.ID},
+		}, nil
+	}
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.UpdateResponse[ProtocolMapperState]{}, err
+	}
+
+	mapper := req.Inputs.toRepresentation()
+	mapper.ID = &req.State.ID
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetBody(mapper).
+		Put(req.Inputs.mappersURL(ctx, req.State.ID))
+	if err := checkResponse(resp, err); err != nil {
+		return infer.UpdateResponse[ProtocolMapperState]{}, fmt.Errorf("failed to update protocol mapper: %w", err)
+	}
+
+	state, err := readProtocolMapperState(ctx, client, token, req.Inputs, req.State.ID)
+	if err != nil {
+		return infer.UpdateResponse[ProtocolMapperState]{}, fmt.Errorf("failed to read protocol mapper state: %w", err)
+	}
+
+	return infer.UpdateResponse[ProtocolMapperState]{
+		Output: state,
+	}, nil
+}
+
+func (m *ProtocolMapper) Delete(ctx context.Context, req infer.DeleteRequest[ProtocolMapperState]) (infer.DeleteResponse, error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		Delete(req.State.mappersURL(ctx, req.State.ID))
+	if err := checkResponse(resp, err); err != nil && !isNotFound(err) {
+		return infer.DeleteResponse{}, fmt.Errorf("failed to delete protocol mapper: %w", err)
+	}
+
+	return infer.DeleteResponse{}, nil
+}
+
+func (m *ProtocolMapper) Read(ctx context.Context, req infer.ReadRequest[ProtocolMapperArgs, ProtocolMapperState]) (infer.ReadResponse[ProtocolMapperArgs, ProtocolMapperState], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.ReadResponse[ProtocolMapperArgs, ProtocolMapperState]{}, err
+	}
+
+	// Protocol mappers are imported with an ID of the form <realm>/clients/<clientId>/<mapperId> or
+	// <realm>/client-scopes/<clientScopeId>/<mapperId>
+	args, id := req.State.ProtocolMapperArgs, req.ID
+	if strings.Contains(req.ID, "/") {
+		parts, err := parseResourceID(req.ID, "<realm>/<clients|client-scopes>/<parentId>/<mapperId>")
+		if err != nil {
+			return infer.ReadResponse[ProtocolMapperArgs, ProtocolMapperState]{}, err
+		}
+		args = ProtocolMapperArgs{RealmID: parts[0]}
+		switch parts[1] {
+		case "clients":
+			args.ClientID = &parts[2]
+		case "client-scopes":
+			args.ClientScopeID = &parts[2]
+		default:
+			return infer.ReadResponse[ProtocolMapperArgs, ProtocolMapperState]{}, fmt.Errorf("invalid ID %q, expected clients or client-scopes after the realm", req.ID)
+		}
+		id = parts[3]
+	}
+
+	state, err := readProtocolMapperState(ctx, client, token, args, id)
+	if err != nil {
+		if isNotFound(err) {
+			return infer.ReadResponse[ProtocolMapperArgs, ProtocolMapperState]{}, nil
+		}
+		return infer.ReadResponse[ProtocolMapperArgs, ProtocolMapperState]{}, fmt.Errorf("failed to read protocol mapper state: %w", err)
+	}
+
+	return infer.ReadResponse[ProtocolMapperArgs, ProtocolMapperState]{
+		ID:     req.ID,
+		Inputs: state.ProtocolMapperArgs,
+		State:  state,
+	}, nil
+}
+
+// importID returns the ID a mapper of this parent is imported with
+func (args ProtocolMapperArgs) importID(id string) string {
+	if args.ClientID != nil {
+		return strings.Join([]string{args.RealmID, "clients", *args.ClientID, id}, "/")
+	}
+	return strings.Join([]string{args.RealmID, "client-scopes", gocloak.PString(args.ClientScopeID), id}, "/")
+}
+
+// mappersURL returns the protocol mapper collection URL of the mapper's parent, followed by any extra path
+func (args ProtocolMapperArgs) mappersURL(ctx context.Context, path ...string) string {
+	parent := []string{"client-scopes", gocloak.PString(args.ClientScopeID)}
+	if args.ClientID != nil {
+		parent = []string{"clients", *args.ClientID}
+	}
+	segments := append(append(parent, "protocol-mappers", "models"), path...)
+	return adminRealmURL(ctx, args.RealmID, segments...)
+}
+
+func (args ProtocolMapperArgs) toRepresentation() gocloak.ProtocolMapperRepresentation {
+	protocol := "openid-connect"
+	if args.Protocol != nil {
+		protocol = *args.Protocol
+	}
+
+	config := args.Config
+	if config == nil {
+		config = map[string]string{}
+	}
+
+	return gocloak.ProtocolMapperRepresentation{
+		Name:            &args.Name,
+		Protocol:        &protocol,
+		ProtocolMapper:  &args.ProtocolMapper,
+		ConsentRequired: args.ConsentRequired,
+		Config:          &config,
+	}
+}
+
+func readProtocolMapperState(ctx context.Context, client KeycloakClient, token string, args ProtocolMapperArgs, id string) (ProtocolMapperState, error) {
+	var mapper gocloak.ProtocolMapperRepresentation
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetResult(&mapper).
+		Get(args.mappersURL(ctx, id))
+	if err := checkResponse(resp, err); err != nil {
+		return ProtocolMapperState{}, err
+	}
+
+	state := ProtocolMapperState{
+		ProtocolMapperArgs: ProtocolMapperArgs{
+			RealmID:         args.RealmID,
+			ClientID:        args.ClientID,
+			ClientScopeID:   args.ClientScopeID,
+			Name:            gocloak.PString(mapper.Name),
+			Protocol:        mapper.Protocol,
+			ProtocolMapper:  gocloak.PString(mapper.ProtocolMapper),
+			ConsentRequired: mapper.ConsentRequired,
+		},
+		ID: id,
+	}
+	if mapper.Config != nil {
+		state.Config = *mapper.Config
+	}
+
+	return state, nil
+}
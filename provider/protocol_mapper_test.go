@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/property"
+)
+
+func TestProtocolMapperCreateReturnsTheImportID(t *testing.T) {
+	mux := http.NewServeMux()
+	var mapper gocloak.ProtocolMapperRepresentation
+	mux.HandleFunc("POST /admin/realms/acme/client-scopes/profile/protocol-mappers/models", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&mapper); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mapper.ID = gocloak.StringP("mapper-1")
+		w.Header().Set("Location", "http://"+r.Host+r.URL.Path+"/mapper-1")
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("POST /admin/realms/acme/clients/app/protocol-mappers/models", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("GET /admin/realms/acme/client-scopes/profile/protocol-mappers/models/mapper-1", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, mapper)
+	})
+	server := newAdminAPIServer(t, mux)
+
+	urn := testURN("ProtocolMapper", "department")
+	inputs := func(parent, id string) property.Map {
+		return property.NewMap(map[string]property.Value{
+			"realmId":        property.New("acme"),
+			parent:           property.New(id),
+			"name":           property.New("department"),
+			"protocol":       property.New("openid-connect"),
+			"protocolMapper": property.New("oidc-usermodel-attribute-mapper"),
+		})
+	}
+	created, err := server.Create(p.CreateRequest{Urn: urn, Properties: inputs("clientScopeId", "profile")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ensureEqual(t, "id", "acme/client-scopes/profile/mapper-1", created.ID)
+	ensureEqual(t, "mapperId", "mapper-1", stringProperty(t, created.Properties, "mapperId"))
+
+	imported, err := server.Read(p.ReadRequest{ID: created.ID, Urn: urn})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ensureEqual(t, "imported clientScopeId", "profile", stringProperty(t, imported.Inputs, "clientScopeId"))
+	ensureEqual(t, "imported name", "department", stringProperty(t, imported.Inputs, "name"))
+
+	if _, err := server.Create(p.CreateRequest{Urn: urn, Properties: inputs("clientId", "app")}); err == nil {
+		t.Error("a create without a Location header succeeded")
+	}
+}
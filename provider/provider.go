@@ -15,18 +15,81 @@ const Name string = "keycloak"
 
 // Provider creates a new instance of the provider.
 func Provider() p.Provider {
+	return newProvider(&ProviderConfig{})
+}
+
+// newProvider builds the provider around a config template, whose clientFactory, if any, replaces the gocloak client
+func newProvider(config *ProviderConfig) p.Provider {
 	p, err := infer.NewProviderBuilder().
 		WithDisplayName("pulumi-keycloak").
 		WithDescription("A Pulumi provider for managing Keycloak resources.").
 		WithHomepage("https://github.com/raushan606/pulumi-qeyqloaq-provider").
 		WithNamespace("qeyqloaq").
-		WithResources(infer.Resource(&Realm{})).
-		WithConfig(infer.Config(&ProviderConfig{})).
+		WithResources(
+			infer.Resource(&Realm{}),
+			infer.Resource(&RealmWebAuthnPasswordlessPolicy{}),
+			infer.Resource(&Organization{}),
+			infer.Resource(&OrganizationMembership{}),
+			infer.Resource(&OrganizationIdentityProvider{}),
+			infer.Resource(&RealmLocalization{}),
+			infer.Resource(&UserFederatedIdentity{}),
+			infer.Resource(&ProtocolMapper{}),
+			infer.Resource(&UsersPermissions{}),
+			infer.Resource(&GroupPermissions{}),
+			infer.Resource(&ClientAuthentication{}),
+			infer.Resource(&SamlClientCertificates{}),
+			infer.Resource(&RealmPartialImport{}),
+			infer.Resource(&UserBulkImport{}),
+		).
+		WithFunctions(
+			infer.Function(&TestSmtpConnection{}),
+			infer.Function(&GetRealm{}),
+			infer.Function(&GetClient{}),
+			infer.Function(&GetUser{}),
+			infer.Function(&SearchUsers{}),
+			infer.Function(&GetGroup{}),
+			infer.Function(&GetRealmRole{}),
+			infer.Function(&GetClientRole{}),
+			infer.Function(&GetAuthenticationFlow{}),
+			infer.Function(&GetRealmKeys{}),
+			infer.Function(&GetIdentityProvider{}),
+			infer.Function(&GetClientServiceAccountUser{}),
+			infer.Function(&GetClientInstallation{}),
+			infer.Function(&GetRealmSamlIdpDescriptor{}),
+			infer.Function(&GetSamlClientSpMetadata{}),
+			infer.Function(&ExportRealm{}),
+			infer.Function(&GetUserSessions{}),
+			infer.Function(&GetClientSessionCount{}),
+			infer.Function(&GetClientSessionStats{}),
+			infer.Function(&GetAdminEvents{}),
+			infer.Function(&GetLoginEvents{}),
+			infer.Function(&GetUserEffectiveRoles{}),
+			infer.Function(&GetUserGroups{}),
+			infer.Function(&GetGroupMembers{}),
+			infer.Function(&GetComponent{}),
+			infer.Function(&GetCompositeRoles{}),
+			infer.Function(&LogoutSessions{}),
+			infer.Function(&ClearRealmCache{}),
+			infer.Function(&ClearUserCache{}),
+			infer.Function(&ClearKeysCache{}),
+			infer.Function(&SendVerifyEmail{}),
+			infer.Function(&ExecuteActionsEmail{}),
+			infer.Function(&ResetUserPassword{}),
+			infer.Function(&RegenerateClientSecret{}),
+			infer.Function(&RevokeUserConsents{}),
+			infer.Function(&RemoveUserCredentials{}),
+			infer.Function(&TestLdapConnection{}),
+			infer.Function(&SyncAllUsers{}),
+			infer.Function(&SyncChangedUsers{}),
+			infer.Function(&Ping{}),
+		).
+		WithConfig(infer.Config(config)).
 		WithModuleMap(map[tokens.ModuleName]tokens.ModuleName{
 			"provider": "index",
 		}).Build()
 	if err != nil {
 		panic(fmt.Errorf("unable to build provider: %w", err))
 	}
-	return p
+	p.DiffConfig = diffConfig
+	return withTracing(withTimeouts(withErrorHints(p)))
 }
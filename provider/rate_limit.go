@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// requestLimiter bounds the number of concurrent admin API requests and the rate at which they start.
+// Limiters are shared by the whole provider process, including clients created before Configure
+type requestLimiter struct {
+	slots    chan struct{} // nil when concurrency is unlimited
+	interval time.Duration // 0 when the rate is unlimited
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+type limiterKey struct {
+	concurrency int
+	perSecond   float64
+}
+
+var (
+	limitersMu sync.Mutex
+	limiters   = map[limiterKey]*requestLimiter{}
+)
+
+// sharedLimiter returns the process-wide limiter for the given limits, or nil when both are unlimited
+func sharedLimiter(concurrency int, perSecond float64) *requestLimiter {
+	if concurrency <= 0 && perSecond <= 0 {
+		return nil
+	}
+
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	key := limiterKey{concurrency: concurrency, perSecond: perSecond}
+	if limiter, ok := limiters[key]; ok {
+		return limiter
+	}
+
+	limiter := &requestLimiter{}
+	if concurrency > 0 {
+		limiter.slots = make(chan struct{}, concurrency)
+	}
+	if perSecond > 0 {
+		limiter.interval = time.Duration(float64(time.Second) / perSecond)
+	}
+	limiters[key] = limiter
+	return limiter
+}
+
+// acquire waits for a free slot and for the next request to be allowed to start
+func (l *requestLimiter) acquire(ctx context.Context) error {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if l.interval > 0 {
+		l.mu.Lock()
+		now := time.Now()
+		if l.next.Before(now) {
+			l.next = now
+		}
+		wait := l.next.Sub(now)
+		l.next = l.next.Add(l.interval)
+		l.mu.Unlock()
+
+		if wait > 0 {
+			timer := time.NewTimer(wait)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				l.release()
+				return ctx.Err()
+			}
+		}
+	}
+
+	return nil
+}
+
+func (l *requestLimiter) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// limitedTransport holds a limiter slot from sending a request until its response body is closed
+type limitedTransport struct {
+	base    http.RoundTripper
+	limiter *requestLimiter
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.acquire(req.Context()); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.limiter.release()
+		return nil, err
+	}
+
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: t.limiter.release}
+	return resp, nil
+}
+
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -3,26 +3,52 @@ package provider
 import (
 	"context"
 	"fmt"
+	"maps"
+	"net/mail"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 
 	gocloak "github.com/Nerzal/gocloak/v13"
+	p "github.com/pulumi/pulumi-go-provider"
 	"github.com/pulumi/pulumi-go-provider/infer"
 )
 
 // Realm represents a Keycloak realm resource with merge strategy
 // This provider only updates fields that are explicitly managed,
-// preserving manual changes to other realm attributes in Keycloak UI
+// preserving manual changes to other realm attributes in Keycloak UI,
+// unless managementMode is full
 type Realm struct{}
 
 type RealmArgs struct {
-	Name            string            `pulumi:"name"`
-	Enabled         *bool             `pulumi:"enabled,optional"`
-	DisplayName     *string           `pulumi:"displayName,optional"`
-	DisplayNameHtml *string           `pulumi:"displayNameHtml,optional"`
-	LoginTheme      *string           `pulumi:"loginTheme,optional"`
-	AccountTheme    *string           `pulumi:"accountTheme,optional"`
-	AdminTheme      *string           `pulumi:"adminTheme,optional"`
-	EmailTheme      *string           `pulumi:"emailTheme,optional"`
-	SmtpServer      *SmtpServerConfig `pulumi:"smtpServer,optional"`
+	Name                                string            `pulumi:"name" provider:"replaceOnChanges"`
+	Enabled                             *bool             `pulumi:"enabled,optional"`
+	DisplayName                         *string           `pulumi:"displayName,optional"`
+	DisplayNameHtml                     *string           `pulumi:"displayNameHtml,optional"`
+	LoginTheme                          *string           `pulumi:"loginTheme,optional"`
+	AccountTheme                        *string           `pulumi:"accountTheme,optional"`
+	AdminTheme                          *string           `pulumi:"adminTheme,optional"`
+	EmailTheme                          *string           `pulumi:"emailTheme,optional"`
+	SmtpServer                          *SmtpServerConfig `pulumi:"smtpServer,optional"`
+	RevokeRefreshToken                  *bool             `pulumi:"revokeRefreshToken,optional"`
+	RefreshTokenMaxReuse                *int              `pulumi:"refreshTokenMaxReuse,optional"`
+	OfflineSessionIdleTimeout           *int              `pulumi:"offlineSessionIdleTimeout,optional"`
+	OfflineSessionMaxLifespanEnabled    *bool             `pulumi:"offlineSessionMaxLifespanEnabled,optional"`
+	OfflineSessionMaxLifespan           *int              `pulumi:"offlineSessionMaxLifespan,optional"`
+	ActionTokenGeneratedByUserLifespan  *int              `pulumi:"actionTokenGeneratedByUserLifespan,optional"`
+	ActionTokenGeneratedByAdminLifespan *int              `pulumi:"actionTokenGeneratedByAdminLifespan,optional"`
+	ActionTokenLifespanOverrides        map[string]int    `pulumi:"actionTokenLifespanOverrides,optional"`
+	UserManagedAccessAllowed            *bool             `pulumi:"userManagedAccessAllowed,optional"`
+	DefaultSignatureAlgorithm           *string           `pulumi:"defaultSignatureAlgorithm,optional"`
+	Attributes                          map[string]string `pulumi:"attributes,optional"`
+	ManagementMode                      *string           `pulumi:"managementMode,optional"`
+	ManagedFields                       []string          `pulumi:"managedFields,optional"`
+	IgnoreFields                        []string          `pulumi:"ignoreFields,optional"`
+	WarnOnDrift                         *bool             `pulumi:"warnOnDrift,optional"`
+	AdoptExisting                       *bool             `pulumi:"adoptExisting,optional"`
+	DeletionProtection                  *bool             `pulumi:"deletionProtection,optional"`
 }
 
 func (args RealmArgs) toKeycloakRealm() gocloak.RealmRepresentation {
@@ -59,6 +85,36 @@ func (args RealmArgs) toKeycloakRealm() gocloak.RealmRepresentation {
 		smtpConfig := convertSmtpConfig(args.SmtpServer)
 		keycloakRealmRepresentation.SMTPServer = &smtpConfig
 	}
+	if args.RevokeRefreshToken != nil {
+		keycloakRealmRepresentation.RevokeRefreshToken = args.RevokeRefreshToken
+	}
+	if args.RefreshTokenMaxReuse != nil {
+		keycloakRealmRepresentation.RefreshTokenMaxReuse = args.RefreshTokenMaxReuse
+	}
+	if args.OfflineSessionIdleTimeout != nil {
+		keycloakRealmRepresentation.OfflineSessionIdleTimeout = args.OfflineSessionIdleTimeout
+	}
+	if args.OfflineSessionMaxLifespanEnabled != nil {
+		keycloakRealmRepresentation.OfflineSessionMaxLifespanEnabled = args.OfflineSessionMaxLifespanEnabled
+	}
+	if args.OfflineSessionMaxLifespan != nil {
+		keycloakRealmRepresentation.OfflineSessionMaxLifespan = args.OfflineSessionMaxLifespan
+	}
+	if args.ActionTokenGeneratedByUserLifespan != nil {
+		keycloakRealmRepresentation.ActionTokenGeneratedByUserLifespan = args.ActionTokenGeneratedByUserLifespan
+	}
+	if args.ActionTokenGeneratedByAdminLifespan != nil {
+		keycloakRealmRepresentation.ActionTokenGeneratedByAdminLifespan = args.ActionTokenGeneratedByAdminLifespan
+	}
+	if args.UserManagedAccessAllowed != nil {
+		keycloakRealmRepresentation.UserManagedAccessAllowed = args.UserManagedAccessAllowed
+	}
+	if args.DefaultSignatureAlgorithm != nil {
+		keycloakRealmRepresentation.DefaultSignatureAlgorithm = args.DefaultSignatureAlgorithm
+	}
+	if attributes := args.realmAttributes(); attributes != nil {
+		keycloakRealmRepresentation.Attributes = &attributes
+	}
 	return keycloakRealmRepresentation
 }
 
@@ -70,20 +126,33 @@ type SmtpServerConfig struct {
 	StartTls *bool   `pulumi:"startTls,optional"`
 	Auth     *bool   `pulumi:"auth,optional"`
 	Username *string `pulumi:"username,optional"`
-	Password *string `pulumi:"password,optional"`
+	Password *string `pulumi:"password,optional" provider:"secret"`
 }
 
 type RealmState struct {
-	ID              string            `pulumi:"realmId"` // The ID of the realm (same as name)
-	Name            string            `pulumi:"name"`
-	Enabled         *bool             `pulumi:"enabled,optional"`
-	DisplayName     *string           `pulumi:"displayName,optional"`
-	DisplayNameHtml *string           `pulumi:"displayNameHtml,optional"`
-	LoginTheme      *string           `pulumi:"loginTheme,optional"`
-	AccountTheme    *string           `pulumi:"accountTheme,optional"`
-	AdminTheme      *string           `pulumi:"adminTheme,optional"`
-	EmailTheme      *string           `pulumi:"emailTheme,optional"`
-	SmtpServer      *SmtpServerConfig `pulumi:"smtpServer,optional"`
+	ID                                  string            `pulumi:"realmId"` // The ID of the realm (same as name)
+	Name                                string            `pulumi:"name"`
+	Enabled                             *bool             `pulumi:"enabled,optional"`
+	DisplayName                         *string           `pulumi:"displayName,optional"`
+	DisplayNameHtml                     *string           `pulumi:"displayNameHtml,optional"`
+	LoginTheme                          *string           `pulumi:"loginTheme,optional"`
+	AccountTheme                        *string           `pulumi:"accountTheme,optional"`
+	AdminTheme                          *string           `pulumi:"adminTheme,optional"`
+	EmailTheme                          *string           `pulumi:"emailTheme,optional"`
+	SmtpServer                          *SmtpServerConfig `pulumi:"smtpServer,optional"`
+	RevokeRefreshToken                  *bool             `pulumi:"revokeRefreshToken,optional"`
+	RefreshTokenMaxReuse                *int              `pulumi:"refreshTokenMaxReuse,optional"`
+	OfflineSessionIdleTimeout           *int              `pulumi:"offlineSessionIdleTimeout,optional"`
+	OfflineSessionMaxLifespanEnabled    *bool             `pulumi:"offlineSessionMaxLifespanEnabled,optional"`
+	OfflineSessionMaxLifespan           *int              `pulumi:"offlineSessionMaxLifespan,optional"`
+	ActionTokenGeneratedByUserLifespan  *int              `pulumi:"actionTokenGeneratedByUserLifespan,optional"`
+	ActionTokenGeneratedByAdminLifespan *int              `pulumi:"actionTokenGeneratedByAdminLifespan,optional"`
+	ActionTokenLifespanOverrides        map[string]int    `pulumi:"actionTokenLifespanOverrides,optional"`
+	UserManagedAccessAllowed            *bool             `pulumi:"userManagedAccessAllowed,optional"`
+	DefaultSignatureAlgorithm           *string           `pulumi:"defaultSignatureAlgorithm,optional"`
+	Attributes                          map[string]string `pulumi:"attributes,optional"`
+	DriftBaseline                       map[string]string `pulumi:"driftBaseline,optional"`
+	DeletionProtection                  *bool             `pulumi:"deletionProtection,optional"`
 }
 
 // Annotate provides schema documentation for the Realm resource
@@ -100,10 +169,22 @@ func (Realm) WireDependencies(f infer.FieldSelector, args *RealmArgs, state *Rea
 	f.OutputField(&state.AdminTheme).DependsOn(f.InputField(&args.AdminTheme))
 	f.OutputField(&state.EmailTheme).DependsOn(f.InputField(&args.EmailTheme))
 	f.OutputField(&state.SmtpServer).DependsOn(f.InputField(&args.SmtpServer))
+	f.OutputField(&state.RevokeRefreshToken).DependsOn(f.InputField(&args.RevokeRefreshToken))
+	f.OutputField(&state.RefreshTokenMaxReuse).DependsOn(f.InputField(&args.RefreshTokenMaxReuse))
+	f.OutputField(&state.OfflineSessionIdleTimeout).DependsOn(f.InputField(&args.OfflineSessionIdleTimeout))
+	f.OutputField(&state.OfflineSessionMaxLifespanEnabled).DependsOn(f.InputField(&args.OfflineSessionMaxLifespanEnabled))
+	f.OutputField(&state.OfflineSessionMaxLifespan).DependsOn(f.InputField(&args.OfflineSessionMaxLifespan))
+	f.OutputField(&state.ActionTokenGeneratedByUserLifespan).DependsOn(f.InputField(&args.ActionTokenGeneratedByUserLifespan))
+	f.OutputField(&state.ActionTokenGeneratedByAdminLifespan).DependsOn(f.InputField(&args.ActionTokenGeneratedByAdminLifespan))
+	f.OutputField(&state.ActionTokenLifespanOverrides).DependsOn(f.InputField(&args.ActionTokenLifespanOverrides))
+	f.OutputField(&state.UserManagedAccessAllowed).DependsOn(f.InputField(&args.UserManagedAccessAllowed))
+	f.OutputField(&state.DefaultSignatureAlgorithm).DependsOn(f.InputField(&args.DefaultSignatureAlgorithm))
+	f.OutputField(&state.Attributes).DependsOn(f.InputField(&args.Attributes))
+	f.OutputField(&state.DeletionProtection).DependsOn(f.InputField(&args.DeletionProtection))
 }
 
 func (args *RealmArgs) Annotate(a infer.Annotator) {
-	a.Describe(&args.Name, "The name of the realm")
+	a.Describe(&args.Name, "The name of the realm. Changing it replaces the realm, deleting everything it contains")
 	a.Describe(&args.Enabled, "Whether the realm is enabled")
 	a.Describe(&args.DisplayName, "Display name shown in the admin console and login pages")
 	a.Describe(&args.DisplayNameHtml, "HTML display name for the realm")
@@ -112,6 +193,30 @@ func (args *RealmArgs) Annotate(a infer.Annotator) {
 	a.Describe(&args.AdminTheme, "Theme used for admin console")
 	a.Describe(&args.EmailTheme, "Theme used for email templates")
 	a.Describe(&args.SmtpServer, "SMTP server configuration for email sending")
+	a.Describe(&args.RevokeRefreshToken, "Whether refresh tokens are revoked after use (refresh token rotation)")
+	a.Describe(&args.RefreshTokenMaxReuse, "Maximum number of times a refresh token can be reused when revocation is enabled")
+	a.Describe(&args.OfflineSessionIdleTimeout, "Time in seconds an offline session can be idle before it expires")
+	a.Describe(&args.OfflineSessionMaxLifespanEnabled, "Whether offline sessions have a maximum lifespan")
+	a.Describe(&args.OfflineSessionMaxLifespan, "Maximum time in seconds before an offline session expires, when offlineSessionMaxLifespanEnabled is set")
+	a.Describe(&args.ActionTokenGeneratedByUserLifespan, "Lifespan in seconds of action tokens requested by users, such as password reset links")
+	a.Describe(&args.ActionTokenGeneratedByAdminLifespan, "Lifespan in seconds of action tokens sent by administrators, such as execute-actions emails")
+	a.Describe(&args.ActionTokenLifespanOverrides, "Per-action token lifespans in seconds keyed by action (e.g., reset-credentials, verify-email, execute-actions)")
+	a.Describe(&args.UserManagedAccessAllowed, "Whether users can manage their own resources and permissions (User-Managed Access)")
+	a.Describe(&args.DefaultSignatureAlgorithm, "Default algorithm used to sign tokens for the realm (e.g., RS256, ES256)")
+	a.Describe(&args.Attributes, "Custom realm attributes (e.g., frontendUrl, acr.loa.map). Merged with the existing attributes instead of replacing them")
+	a.Describe(&args.ManagementMode, "How fields without an input are treated, overriding the provider's managementMode. merge leaves them "+
+		"as they are in Keycloak; full resets them to Keycloak's defaults, clears the SMTP server and removes attributes and "+
+		"token lifespan overrides dropped from the inputs")
+	a.Describe(&args.ManagedFields, "Fields the provider keeps in sync, e.g. [\"displayName\", \"smtpServer\"]. When set, other fields are "+
+		"only applied when the realm is created and are left alone afterwards, even in full management mode")
+	a.Describe(&args.IgnoreFields, "Fields only applied when the realm is created and never updated or diffed afterwards, e.g. to leave "+
+		"loginTheme to the admin console. Takes precedence over managedFields")
+	a.Describe(&args.WarnOnDrift, "Whether to record the unmanaged realm settings and attributes, and warn on preview, update and refresh "+
+		"about those changed outside of Pulumi since, e.g. in the admin console. A refresh acknowledges the changes, which are never reverted")
+	a.Describe(&args.AdoptExisting, "Whether to take over a realm of the same name that already exists, updating it to match the inputs. "+
+		"Otherwise creating the resource fails, and the realm can be brought under management with pulumi import. "+
+		"An adopted realm is deleted with the resource like any other")
+	a.Describe(&args.DeletionProtection, "Whether to refuse deleting or replacing the realm. Set it to false and apply before destroying the realm")
 
 	a.SetDefault(&args.Enabled, true)
 }
@@ -142,43 +247,304 @@ func (state *RealmState) Annotate(a infer.Annotator) {
 	a.Describe(&state.AdminTheme, "Theme used for admin console")
 	a.Describe(&state.EmailTheme, "Theme used for email templates")
 	a.Describe(&state.SmtpServer, "SMTP server configuration for email sending")
+	a.Describe(&state.RevokeRefreshToken, "Whether refresh tokens are revoked after use (refresh token rotation)")
+	a.Describe(&state.RefreshTokenMaxReuse, "Maximum number of times a refresh token can be reused when revocation is enabled")
+	a.Describe(&state.OfflineSessionIdleTimeout, "Time in seconds an offline session can be idle before it expires")
+	a.Describe(&state.OfflineSessionMaxLifespanEnabled, "Whether offline sessions have a maximum lifespan")
+	a.Describe(&state.OfflineSessionMaxLifespan, "Maximum time in seconds before an offline session expires, when offlineSessionMaxLifespanEnabled is set")
+	a.Describe(&state.ActionTokenGeneratedByUserLifespan, "Lifespan in seconds of action tokens requested by users, such as password reset links")
+	a.Describe(&state.ActionTokenGeneratedByAdminLifespan, "Lifespan in seconds of action tokens sent by administrators, such as execute-actions emails")
+	a.Describe(&state.ActionTokenLifespanOverrides, "Per-action token lifespans in seconds keyed by action")
+	a.Describe(&state.UserManagedAccessAllowed, "Whether users can manage their own resources and permissions (User-Managed Access)")
+	a.Describe(&state.DefaultSignatureAlgorithm, "Default algorithm used to sign tokens for the realm (e.g., RS256, ES256)")
+	a.Describe(&state.Attributes, "Managed custom realm attributes")
+	a.Describe(&state.DriftBaseline, "Unmanaged realm settings and attributes as last seen, when warnOnDrift is set")
+	a.Describe(&state.DeletionProtection, "Whether deleting or replacing the realm is refused")
+}
+
+// previewState is the state reported during previews, before Keycloak fills in the fields not managed here
+func (args RealmArgs) previewState() RealmState {
+	return RealmState{
+		ID:                                  args.Name,
+		Name:                                args.Name,
+		Enabled:                             args.Enabled,
+		DisplayName:                         args.DisplayName,
+		DisplayNameHtml:                     args.DisplayNameHtml,
+		LoginTheme:                          args.LoginTheme,
+		AccountTheme:                        args.AccountTheme,
+		AdminTheme:                          args.AdminTheme,
+		EmailTheme:                          args.EmailTheme,
+		SmtpServer:                          args.SmtpServer,
+		RevokeRefreshToken:                  args.RevokeRefreshToken,
+		RefreshTokenMaxReuse:                args.RefreshTokenMaxReuse,
+		OfflineSessionIdleTimeout:           args.OfflineSessionIdleTimeout,
+		OfflineSessionMaxLifespanEnabled:    args.OfflineSessionMaxLifespanEnabled,
+		OfflineSessionMaxLifespan:           args.OfflineSessionMaxLifespan,
+		ActionTokenGeneratedByUserLifespan:  args.ActionTokenGeneratedByUserLifespan,
+		ActionTokenGeneratedByAdminLifespan: args.ActionTokenGeneratedByAdminLifespan,
+		ActionTokenLifespanOverrides:        args.ActionTokenLifespanOverrides,
+		UserManagedAccessAllowed:            args.UserManagedAccessAllowed,
+		DefaultSignatureAlgorithm:           args.DefaultSignatureAlgorithm,
+		Attributes:                          args.Attributes,
+		DeletionProtection:                  args.DeletionProtection,
+	}
+}
+
+// importArgs returns the inputs of an imported realm. Its attributes are left unmanaged, since the
+// live map also holds the many attributes Keycloak maintains itself
+func (state RealmState) importArgs() RealmArgs {
+	return RealmArgs{
+		Name:                                state.Name,
+		Enabled:                             state.Enabled,
+		DisplayName:                         state.DisplayName,
+		DisplayNameHtml:                     state.DisplayNameHtml,
+		LoginTheme:                          state.LoginTheme,
+		AccountTheme:                        state.AccountTheme,
+		AdminTheme:                          state.AdminTheme,
+		EmailTheme:                          state.EmailTheme,
+		SmtpServer:                          state.SmtpServer,
+		RevokeRefreshToken:                  state.RevokeRefreshToken,
+		RefreshTokenMaxReuse:                state.RefreshTokenMaxReuse,
+		OfflineSessionIdleTimeout:           state.OfflineSessionIdleTimeout,
+		OfflineSessionMaxLifespanEnabled:    state.OfflineSessionMaxLifespanEnabled,
+		OfflineSessionMaxLifespan:           state.OfflineSessionMaxLifespan,
+		ActionTokenGeneratedByUserLifespan:  state.ActionTokenGeneratedByUserLifespan,
+		ActionTokenGeneratedByAdminLifespan: state.ActionTokenGeneratedByAdminLifespan,
+		ActionTokenLifespanOverrides:        state.ActionTokenLifespanOverrides,
+		UserManagedAccessAllowed:            state.UserManagedAccessAllowed,
+		DefaultSignatureAlgorithm:           state.DefaultSignatureAlgorithm,
+	}
+}
+
+// Management modes: merge only reconciles the fields set in the inputs, while full also resets the unset fields to
+// Keycloak's defaults
+const (
+	managementModeMerge = "merge"
+	managementModeFull  = "full"
+)
+
+func validateManagementMode(mode *string) error {
+	if mode == nil || *mode == managementModeMerge || *mode == managementModeFull {
+		return nil
+	}
+	return fmt.Errorf("invalid managementMode %q: must be %s or %s", *mode, managementModeMerge, managementModeFull)
+}
+
+// fullManagement reports whether the realm is managed in full mode, per its own managementMode or else the provider's
+func (args RealmArgs) fullManagement(config ProviderConfig) bool {
+	mode := config.ManagementMode
+	if args.ManagementMode != nil {
+		mode = args.ManagementMode
+	}
+	return mode != nil && *mode == managementModeFull
+}
+
+// withDefaults fills the unset fields with the values Keycloak gives a new realm, for full management mode
+func (args RealmArgs) withDefaults() RealmArgs {
+	if args.Enabled == nil {
+		args.Enabled = gocloak.BoolP(true)
+	}
+	for _, field := range []**string{&args.DisplayName, &args.DisplayNameHtml, &args.LoginTheme, &args.AccountTheme, &args.AdminTheme, &args.EmailTheme} {
+		if *field == nil {
+			*field = gocloak.StringP("")
+		}
+	}
+	if args.RevokeRefreshToken == nil {
+		args.RevokeRefreshToken = gocloak.BoolP(false)
+	}
+	if args.RefreshTokenMaxReuse == nil {
+		args.RefreshTokenMaxReuse = gocloak.IntP(0)
+	}
+	if args.OfflineSessionIdleTimeout == nil {
+		args.OfflineSessionIdleTimeout = gocloak.IntP(2592000)
+	}
+	if args.OfflineSessionMaxLifespanEnabled == nil {
+		args.OfflineSessionMaxLifespanEnabled = gocloak.BoolP(false)
+	}
+	if args.OfflineSessionMaxLifespan == nil {
+		args.OfflineSessionMaxLifespan = gocloak.IntP(5184000)
+	}
+	if args.ActionTokenGeneratedByUserLifespan == nil {
+		args.ActionTokenGeneratedByUserLifespan = gocloak.IntP(300)
+	}
+	if args.ActionTokenGeneratedByAdminLifespan == nil {
+		args.ActionTokenGeneratedByAdminLifespan = gocloak.IntP(43200)
+	}
+	if args.UserManagedAccessAllowed == nil {
+		args.UserManagedAccessAllowed = gocloak.BoolP(false)
+	}
+	if args.DefaultSignatureAlgorithm == nil {
+		args.DefaultSignatureAlgorithm = gocloak.StringP("RS256")
+	}
+	return args
+}
+
+// staleAttributes lists the realm attributes that full management mode removes: previously managed attributes that
+// were dropped from the inputs, and token lifespan overrides without an input
+func (args RealmArgs) staleAttributes(current, previous map[string]string) []string {
+	var stale []string
+	if args.manages("attributes") {
+		for key := range previous {
+			_, managed := args.Attributes[key]
+			_, exists := current[key]
+			if !managed && exists {
+				stale = append(stale, key)
+			}
+		}
+	}
+	if args.manages("actionTokenLifespanOverrides") {
+		for key := range current {
+			action, ok := strings.CutPrefix(key, actionTokenLifespanPrefix)
+			if !ok {
+				continue
+			}
+			_, overridden := args.ActionTokenLifespanOverrides[action]
+			_, managed := args.Attributes[key]
+			if !overridden && !managed {
+				stale = append(stale, key)
+			}
+		}
+	}
+	return stale
+}
+
+// realmFieldClearers unset each realm field that managedFields and ignoreFields can name
+var realmFieldClearers = map[string]func(args *RealmArgs){
+	"enabled":                             func(args *RealmArgs) { args.Enabled = nil },
+	"displayName":                         func(args *RealmArgs) { args.DisplayName = nil },
+	"displayNameHtml":                     func(args *RealmArgs) { args.DisplayNameHtml = nil },
+	"loginTheme":                          func(args *RealmArgs) { args.LoginTheme = nil },
+	"accountTheme":                        func(args *RealmArgs) { args.AccountTheme = nil },
+	"adminTheme":                          func(args *RealmArgs) { args.AdminTheme = nil },
+	"emailTheme":                          func(args *RealmArgs) { args.EmailTheme = nil },
+	"smtpServer":                          func(args *RealmArgs) { args.SmtpServer = nil },
+	"revokeRefreshToken":                  func(args *RealmArgs) { args.RevokeRefreshToken = nil },
+	"refreshTokenMaxReuse":                func(args *RealmArgs) { args.RefreshTokenMaxReuse = nil },
+	"offlineSessionIdleTimeout":           func(args *RealmArgs) { args.OfflineSessionIdleTimeout = nil },
+	"offlineSessionMaxLifespanEnabled":    func(args *RealmArgs) { args.OfflineSessionMaxLifespanEnabled = nil },
+	"offlineSessionMaxLifespan":           func(args *RealmArgs) { args.OfflineSessionMaxLifespan = nil },
+	"actionTokenGeneratedByUserLifespan":  func(args *RealmArgs) { args.ActionTokenGeneratedByUserLifespan = nil },
+	"actionTokenGeneratedByAdminLifespan": func(args *RealmArgs) { args.ActionTokenGeneratedByAdminLifespan = nil },
+	"actionTokenLifespanOverrides":        func(args *RealmArgs) { args.ActionTokenLifespanOverrides = nil },
+	"userManagedAccessAllowed":            func(args *RealmArgs) { args.UserManagedAccessAllowed = nil },
+	"defaultSignatureAlgorithm":           func(args *RealmArgs) { args.DefaultSignatureAlgorithm = nil },
+	"attributes":                          func(args *RealmArgs) { args.Attributes = nil },
+}
+
+// manages reports whether the provider keeps a realm field in sync after creation, per managedFields and ignoreFields
+func (args RealmArgs) manages(field string) bool {
+	if slices.Contains(args.IgnoreFields, field) {
+		return false
+	}
+	return args.ManagedFields == nil || slices.Contains(args.ManagedFields, field)
+}
+
+// withoutUnmanagedFields unsets the fields the provider does not keep in sync, so that they are neither diffed nor updated
+func (args RealmArgs) withoutUnmanagedFields() RealmArgs {
+	for field, clear := range realmFieldClearers {
+		if !args.manages(field) {
+			clear(&args)
+		}
+	}
+	return args
+}
+
+// reconciledArgs returns the fields to reconcile the realm with, and whether it is managed in full mode
+func (args RealmArgs) reconciledArgs(config ProviderConfig) (RealmArgs, bool) {
+	full := args.fullManagement(config)
+	if full {
+		args = args.withDefaults()
+	}
+	return args.withoutUnmanagedFields(), full
 }
 
 func (r *Realm) Create(ctx context.Context, req infer.CreateRequest[RealmArgs]) (infer.CreateResponse[RealmState], error) {
 	config := infer.GetConfig[ProviderConfig](ctx)
-	client := gocloak.NewClient(config.URL)
+	adopt := req.Inputs.AdoptExisting != nil && *req.Inputs.AdoptExisting
+
+	// Only an adopted realm is looked up before the preview, which shows the settings it keeps. While the name is
+	// unknown, the realm may turn out to exist
+	var client KeycloakClient
+	var token string
+	var err error
+	exists := adopt && req.Inputs.Name == ""
+	if adopt && !exists {
+		client, token, err = login(ctx)
+		if err != nil {
+			return infer.CreateResponse[RealmState]{}, err
+		}
+		exists, err = realmExistsWithClient(ctx, client, token, req.Inputs.Name)
+		if err != nil {
+			return infer.CreateResponse[RealmState]{}, fmt.Errorf("failed to check if realm exists: %w", err)
+		}
+	}
 
-	token, err := client.LoginAdmin(ctx, config.Username, config.Password, *config.Realm)
-	if err != nil {
-		return infer.CreateResponse[RealmState]{}, fmt.Errorf("failed to authenticate: %w", err)
+	// Realm-scoped admins manage their own realm through realm-management roles but cannot create realms
+	if adminRealm := config.adminRealm(); !exists && adminRealm != "master" {
+		return infer.CreateResponse[RealmState]{}, fmt.Errorf("creating realm %q requires an admin of the master realm, but the provider authenticates against realm %q", req.Inputs.Name, adminRealm)
 	}
 
 	if req.DryRun {
+		preview := req.Inputs.previewState()
+		if exists {
+			args, full := req.Inputs.reconciledArgs(config)
+			preview, err = previewRealm(ctx, client, token, req.Inputs, args, full, nil)
+			if err != nil {
+				return infer.CreateResponse[RealmState]{}, err
+			}
+		}
 		return infer.CreateResponse[RealmState]{
-			ID: req.Inputs.Name,
-			Output: RealmState{
-				ID:              req.Inputs.Name,
-				Name:            req.Inputs.Name,
-				Enabled:         req.Inputs.Enabled,
-				DisplayName:     req.Inputs.DisplayName,
-				DisplayNameHtml: req.Inputs.DisplayNameHtml,
-				LoginTheme:      req.Inputs.LoginTheme,
-				AccountTheme:    req.Inputs.AccountTheme,
-				AdminTheme:      req.Inputs.AdminTheme,
-				EmailTheme:      req.Inputs.EmailTheme,
-				SmtpServer:      req.Inputs.SmtpServer,
-			},
+			ID:     req.Inputs.Name,
+			Output: preview,
 		}, nil
 	}
 
-	_, err = client.CreateRealm(ctx, token.AccessToken, req.Inputs.toKeycloakRealm())
-	if err != nil {
-		return infer.CreateResponse[RealmState]{}, fmt.Errorf("failed to create realm: %w", err)
+	if !adopt {
+		client, token, err = login(ctx)
+		if err != nil {
+			return infer.CreateResponse[RealmState]{}, err
+		}
+	}
+
+	// From here on the realm exists, so failures return a partial state: Pulumi tracks the realm as failed to
+	// initialize and finishes it with an Update on the next run, instead of leaving an orphan that blocks retries
+	partial := func(reason string, err error) (infer.CreateResponse[RealmState], error) {
+		state := req.Inputs.previewState()
+		if exists {
+			// Nothing is known about the settings of an adopted realm
+			state = RealmState{ID: req.Inputs.Name, Name: req.Inputs.Name, DeletionProtection: req.Inputs.DeletionProtection}
+		}
+		return infer.CreateResponse[RealmState]{
+			ID:     req.Inputs.Name,
+			Output: state,
+		}, infer.ResourceInitFailedError{Reasons: []string{fmt.Sprintf("%s: %v", reason, err)}}
 	}
 
-	state, err := readRealmState(ctx, client, token.AccessToken, req.Inputs.Name)
+	if exists {
+		p.GetLogger(ctx).Infof("adopting existing realm %s", req.Inputs.Name)
+		args, full := req.Inputs.reconciledArgs(config)
+		if err := updateManagedFields(ctx, client, token, args, full, nil); err != nil {
+			return partial("failed to update adopted realm", err)
+		}
+	} else {
+		_, err = client.CreateRealm(ctx, token, req.Inputs.toKeycloakRealm())
+		if isConflict(err) {
+			return infer.CreateResponse[RealmState]{}, fmt.Errorf("realm %q already exists: bring it under management with "+
+				"pulumi import keycloak:index:Realm <name> %s, or set adoptExisting to take it over", req.Inputs.Name, req.Inputs.Name)
+		}
+		if err != nil {
+			return infer.CreateResponse[RealmState]{}, fmt.Errorf("failed to create realm: %w", err)
+		}
+	}
+
+	state, err := readRealmState(ctx, client, token, req.Inputs.Name)
 	if err != nil {
-		return infer.CreateResponse[RealmState]{}, fmt.Errorf("failed to read realm state: %w", err)
+		return partial("failed to read realm state", err)
+	}
+	state.Attributes = managedAttributes(state.Attributes, req.Inputs.Attributes)
+	state.DeletionProtection = req.Inputs.DeletionProtection
+	if err := recordDriftBaseline(ctx, client, token, config, req.Inputs, nil, &state); err != nil {
+		return partial("failed to record drift baseline", err)
 	}
 
 	return infer.CreateResponse[RealmState]{
@@ -187,74 +553,175 @@ func (r *Realm) Create(ctx context.Context, req infer.CreateRequest[RealmArgs])
 	}, nil
 }
 
+// StateMigrations upgrades realm states recorded by older provider versions when they are next read, diffed or
+// updated, so existing stacks keep working without a refresh
+func (*Realm) StateMigrations(context.Context) []infer.StateMigrationFunc[RealmState] {
+	return []infer.StateMigrationFunc[RealmState]{
+		infer.StateMigration(migrateFlatSmtpServer),
+	}
+}
+
+// realmStateFlatSmtp is a realm state that recorded smtpServer in Keycloak's own format, a flat map of strings with
+// keys such as starttls, user and fromDisplayName, instead of as SmtpServerConfig
+type realmStateFlatSmtp struct {
+	RealmState
+	SmtpServer map[string]string `pulumi:"smtpServer,optional"`
+}
+
+// flatSmtpKeys only appear in Keycloak's SMTP format. port and auth are also SmtpServerConfig fields, but hold
+// strings only in the flat format, which is all a state decoded as realmStateFlatSmtp can hold
+var flatSmtpKeys = []string{"fromDisplayName", "starttls", "ssl", "user", "port", "auth"}
+
+func migrateFlatSmtpServer(_ context.Context, old realmStateFlatSmtp) (infer.MigrationResult[RealmState], error) {
+	flat := slices.ContainsFunc(flatSmtpKeys, func(key string) bool {
+		_, ok := old.SmtpServer[key]
+		return ok
+	})
+	if !flat {
+		return infer.MigrationResult[RealmState]{}, nil
+	}
+
+	state := old.RealmState
+	state.SmtpServer = convertFromKeycloakSmtp(old.SmtpServer)
+	return infer.MigrationResult[RealmState]{Result: &state}, nil
+}
+
 func (*Realm) Check(ctx context.Context, req infer.CheckRequest) (infer.CheckResponse[RealmArgs], error) {
-	args, f, err := infer.DefaultCheck[RealmArgs](ctx, req.NewInputs)
+	args, failures, err := infer.DefaultCheck[RealmArgs](ctx, req.NewInputs)
+	if err != nil {
+		return infer.CheckResponse[RealmArgs]{Inputs: args, Failures: failures}, err
+	}
+
+	// Values that depend on other resources are unknown during previews and checked once known
+	if !req.NewInputs.Get("name").IsComputed() && !realmNamePattern.MatchString(args.Name) {
+		failures = append(failures, p.CheckFailure{
+			Property: "name",
+			Reason:   "realm names must be non-empty and may only contain letters, digits, '.', '_' and '-'",
+		})
+	}
+	if args.SmtpServer != nil && !req.NewInputs.Get("smtpServer").HasComputed() {
+		failures = append(failures, args.SmtpServer.validate()...)
+	}
+	if err := validateManagementMode(args.ManagementMode); err != nil {
+		failures = append(failures, p.CheckFailure{Property: "managementMode", Reason: err.Error()})
+	}
+	for property, fields := range map[string][]string{"managedFields": args.ManagedFields, "ignoreFields": args.IgnoreFields} {
+		for _, field := range fields {
+			if _, ok := realmFieldClearers[field]; !ok {
+				failures = append(failures, p.CheckFailure{Property: property, Reason: fmt.Sprintf("unknown realm field %q", field)})
+			}
+		}
+	}
+
 	return infer.CheckResponse[RealmArgs]{
 		Inputs:   args,
-		Failures: f,
-	}, err
+		Failures: failures,
+	}, nil
+}
+
+// realmNamePattern matches the realm names that are safe to use in admin API and login URLs
+var realmNamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// validate checks SMTP settings Keycloak would otherwise only reject when the realm is saved
+func (smtp SmtpServerConfig) validate() []p.CheckFailure {
+	var failures []p.CheckFailure
+	if smtp.Port != nil && (*smtp.Port < 1 || *smtp.Port > 65535) {
+		failures = append(failures, p.CheckFailure{Property: "smtpServer.port", Reason: fmt.Sprintf("port %d is not between 1 and 65535", *smtp.Port)})
+	}
+	if smtp.From != nil {
+		if address, err := mail.ParseAddress(*smtp.From); err != nil || address.Address != *smtp.From {
+			failures = append(failures, p.CheckFailure{Property: "smtpServer.from", Reason: fmt.Sprintf("%q is not an email address", *smtp.From)})
+		}
+	}
+	if smtp.Auth != nil && *smtp.Auth {
+		if smtp.Username == nil || *smtp.Username == "" {
+			failures = append(failures, p.CheckFailure{Property: "smtpServer.username", Reason: "username is required when auth is enabled"})
+		}
+		if smtp.Password == nil || *smtp.Password == "" {
+			failures = append(failures, p.CheckFailure{Property: "smtpServer.password", Reason: "password is required when auth is enabled"})
+		}
+	}
+	return failures
 }
 
 // Update implementation - only updates managed fields
 func (r *Realm) Update(ctx context.Context, req infer.UpdateRequest[RealmArgs, RealmState]) (infer.UpdateResponse[RealmState], error) {
 	config := infer.GetConfig[ProviderConfig](ctx)
-	client := gocloak.NewClient(config.URL)
 
-	token, err := client.LoginAdmin(ctx, config.Username, config.Password, *config.Realm)
+	// Update only managed fields, which in full management mode include the unset ones
+	args, full := req.Inputs.reconciledArgs(config)
+
+	if args.Enabled != nil && !*args.Enabled {
+		if err := config.checkRealmProtection(args.Name, "disable"); err != nil {
+			return infer.UpdateResponse[RealmState]{}, err
+		}
+	}
+
+	client, token, err := login(ctx)
 	if err != nil {
-		return infer.UpdateResponse[RealmState]{}, fmt.Errorf("failed to authenticate: %w", err)
+		return infer.UpdateResponse[RealmState]{}, err
 	}
 
 	if req.DryRun {
+		preview, err := previewRealm(ctx, client, token, req.Inputs, args, full, req.State.Attributes)
+		if err != nil {
+			return infer.UpdateResponse[RealmState]{}, err
+		}
+		preview.DriftBaseline = req.State.DriftBaseline
 		return infer.UpdateResponse[RealmState]{
-			Output: RealmState{
-				ID:              req.Inputs.Name,
-				Name:            req.Inputs.Name,
-				Enabled:         req.Inputs.Enabled,
-				DisplayName:     req.Inputs.DisplayName,
-				DisplayNameHtml: req.Inputs.DisplayNameHtml,
-				LoginTheme:      req.Inputs.LoginTheme,
-				AccountTheme:    req.Inputs.AccountTheme,
-				AdminTheme:      req.Inputs.AdminTheme,
-				EmailTheme:      req.Inputs.EmailTheme,
-				SmtpServer:      req.Inputs.SmtpServer,
-			},
+			Output: preview,
 		}, nil
 	}
 
-	// Update only managed fields (merge strategy)
-	err = updateManagedFields(ctx, client, token.AccessToken, req.Inputs)
+	err = updateManagedFields(ctx, client, token, args, full, req.State.Attributes)
 	if err != nil {
 		return infer.UpdateResponse[RealmState]{}, fmt.Errorf("failed to update managed fields: %w", err)
 	}
 
 	// Read the current state
-	state, err := readRealmState(ctx, client, token.AccessToken, req.Inputs.Name)
+	state, err := readRealmState(ctx, client, token, req.Inputs.Name)
 	if err != nil {
 		return infer.UpdateResponse[RealmState]{}, fmt.Errorf("failed to read realm state: %w", err)
 	}
+	state.Attributes = managedAttributes(state.Attributes, req.Inputs.Attributes)
+	state.DeletionProtection = req.Inputs.DeletionProtection
+	if err := recordDriftBaseline(ctx, client, token, config, req.Inputs, req.State.DriftBaseline, &state); err != nil {
+		return infer.UpdateResponse[RealmState]{}, err
+	}
 
 	return infer.UpdateResponse[RealmState]{
 		Output: state,
 	}, nil
 }
 
+// checkDeletionProtection refuses to delete a realm whose deletionProtection was set when it was last applied
+func (state RealmState) checkDeletionProtection(operation string) error {
+	if ptrBoolValue(state.DeletionProtection) {
+		return fmt.Errorf("refusing to %s realm %q, which has deletionProtection enabled: set deletionProtection to false and run pulumi up first", operation, state.Name)
+	}
+	return nil
+}
+
 func (r *Realm) Delete(ctx context.Context, req infer.DeleteRequest[RealmState]) (infer.DeleteResponse, error) {
 	config := infer.GetConfig[ProviderConfig](ctx)
-	client := gocloak.NewClient(config.URL)
+	if err := config.checkRealmProtection(req.State.Name, "delete"); err != nil {
+		return infer.DeleteResponse{}, err
+	}
+	if err := req.State.checkDeletionProtection("delete"); err != nil {
+		return infer.DeleteResponse{}, err
+	}
 
-	token, err := client.LoginAdmin(ctx, config.Username, config.Password, *config.Realm)
+	client, token, err := login(ctx)
 	if err != nil {
-		return infer.DeleteResponse{}, fmt.Errorf("failed to authenticate: %w", err)
+		return infer.DeleteResponse{}, err
 	}
 
-	err = client.DeleteRealm(ctx, token.AccessToken, req.State.Name)
+	err = client.DeleteRealm(ctx, token, req.State.Name)
+	if isNotFound(err) {
+		p.GetLogger(ctx).Infof("realm %s was already deleted", req.State.Name)
+		return infer.DeleteResponse{}, nil
+	}
 	if err != nil {
-		// Check if realm was already deleted
-		exists, checkErr := realmExistsWithClient(ctx, client, token.AccessToken, req.State.Name)
-		if checkErr == nil && !exists {
-			return infer.DeleteResponse{}, nil
-		}
 		return infer.DeleteResponse{}, fmt.Errorf("failed to delete realm: %w", err)
 	}
 
@@ -263,11 +730,9 @@ func (r *Realm) Delete(ctx context.Context, req infer.DeleteRequest[RealmState])
 
 func (r *Realm) Read(ctx context.Context, req infer.ReadRequest[RealmArgs, RealmState]) (infer.ReadResponse[RealmArgs, RealmState], error) {
 	config := infer.GetConfig[ProviderConfig](ctx)
-	client := gocloak.NewClient(config.URL)
-
-	token, err := client.LoginAdmin(ctx, config.Username, config.Password, *config.Realm)
+	client, token, err := login(ctx)
 	if err != nil {
-		return infer.ReadResponse[RealmArgs, RealmState]{}, fmt.Errorf("failed to authenticate: %w", err)
+		return infer.ReadResponse[RealmArgs, RealmState]{}, err
 	}
 
 	realmName := req.ID
@@ -280,89 +745,152 @@ func (r *Realm) Read(ctx context.Context, req infer.ReadRequest[RealmArgs, Realm
 		return infer.ReadResponse[RealmArgs, RealmState]{}, nil
 	}
 
-	exists, err := realmExistsWithClient(ctx, client, token.AccessToken, realmName)
+	state, err := readRealmState(ctx, client, token, realmName)
 	if err != nil {
-		return infer.ReadResponse[RealmArgs, RealmState]{}, fmt.Errorf("failed to check if realm exists: %w", err)
+		// If realm doesn't exist, signal deletion by returning empty response
+		if isNotFound(err) {
+			return infer.ReadResponse[RealmArgs, RealmState]{}, nil
+		}
+		return infer.ReadResponse[RealmArgs, RealmState]{}, fmt.Errorf("failed to read realm state: %w", err)
 	}
+	state.Attributes = managedAttributes(state.Attributes, req.Inputs.Attributes)
+	state.DeletionProtection = req.Inputs.DeletionProtection
 
-	// If realm doesn't exist, signal deletion by returning empty response
-	if !exists {
-		return infer.ReadResponse[RealmArgs, RealmState]{}, nil
+	// An import has no inputs yet, so they are taken from the live realm
+	inputs := req.Inputs
+	if inputs.Name == "" {
+		inputs = state.importArgs()
 	}
 
-	state, err := readRealmState(ctx, client, token.AccessToken, realmName)
-	if err != nil {
-		return infer.ReadResponse[RealmArgs, RealmState]{}, fmt.Errorf("failed to read realm state: %w", err)
+	if err := recordDriftBaseline(ctx, client, token, config, inputs, nil, &state); err != nil {
+		return infer.ReadResponse[RealmArgs, RealmState]{}, err
 	}
+	reportDrift(ctx, realmName, req.State.DriftBaseline, state.DriftBaseline)
 
 	return infer.ReadResponse[RealmArgs, RealmState]{
 		ID:     realmName,
-		Inputs: req.Inputs,
+		Inputs: inputs,
 		State:  state,
 	}, nil
 }
 
 // Diff computes the difference between two states and determines if an update is needed
 func (r *Realm) Diff(ctx context.Context, req infer.DiffRequest[RealmArgs, RealmState]) (infer.DiffResponse, error) {
-	// Check if the realm name changed (requires replacement)
-	if req.Inputs.Name != req.State.Name {
-		return infer.DiffResponse{
-			HasChanges:          true,
-			DeleteBeforeReplace: true,
-		}, nil
+	config := infer.GetConfig[ProviderConfig](ctx)
+	inputs, full := req.Inputs.reconciledArgs(config)
+	state := req.State
+	diff := map[string]p.PropertyDiff{}
+
+	// Fields without an input are not managed, so only set inputs that differ from the realm are updated
+	changed := func(property string, different bool) {
+		if different {
+			diff[property] = p.PropertyDiff{Kind: p.Update}
+		}
 	}
 
-	// Check if any managed fields changed
-	hasChanges := false
-
-	if req.Inputs.Enabled != nil && !ptrBoolEqual(req.State.Enabled, req.Inputs.Enabled) {
-		hasChanges = true
-	}
+	// The realm name is its identifier, so changing it requires a replacement. Realm names are unique and compared
+	// case-insensitively by some databases, so the old realm is deleted before the new one is created
+	if inputs.Name != state.Name {
+		if err := config.checkRealmProtection(state.Name, "replace"); err != nil {
+			return infer.DiffResponse{}, err
+		}
+		if err := state.checkDeletionProtection("replace"); err != nil {
+			return infer.DiffResponse{}, err
+		}
+		diff["name"] = p.PropertyDiff{Kind: p.UpdateReplace}
+		p.GetLogger(ctx).Warningf("renaming realm %q to %q replaces it: %q is deleted with all its clients, users, groups, roles "+
+			"and sessions, then %q is created empty", state.Name, inputs.Name, state.Name, inputs.Name)
+	}
+
+	changed("deletionProtection", ptrBoolValue(state.DeletionProtection) != ptrBoolValue(inputs.DeletionProtection))
+	changed("enabled", inputs.Enabled != nil && !ptrBoolEqual(state.Enabled, inputs.Enabled))
+	changed("displayName", inputs.DisplayName != nil && !realmStringEqual(state.DisplayName, inputs.DisplayName))
+	changed("displayNameHtml", inputs.DisplayNameHtml != nil && !realmStringEqual(state.DisplayNameHtml, inputs.DisplayNameHtml))
+	changed("loginTheme", inputs.LoginTheme != nil && !realmStringEqual(state.LoginTheme, inputs.LoginTheme))
+	changed("accountTheme", inputs.AccountTheme != nil && !realmStringEqual(state.AccountTheme, inputs.AccountTheme))
+	changed("adminTheme", inputs.AdminTheme != nil && !realmStringEqual(state.AdminTheme, inputs.AdminTheme))
+	changed("emailTheme", inputs.EmailTheme != nil && !realmStringEqual(state.EmailTheme, inputs.EmailTheme))
+	changed("smtpServer", inputs.SmtpServer != nil && !smtpConfigContained(convertSmtpConfig(inputs.SmtpServer), convertSmtpConfig(state.SmtpServer)))
+	changed("revokeRefreshToken", inputs.RevokeRefreshToken != nil && !ptrBoolEqual(state.RevokeRefreshToken, inputs.RevokeRefreshToken))
+	changed("refreshTokenMaxReuse", inputs.RefreshTokenMaxReuse != nil && !ptrIntEqual(state.RefreshTokenMaxReuse, inputs.RefreshTokenMaxReuse))
+	changed("offlineSessionIdleTimeout", inputs.OfflineSessionIdleTimeout != nil && !ptrIntEqual(state.OfflineSessionIdleTimeout, inputs.OfflineSessionIdleTimeout))
+	changed("offlineSessionMaxLifespanEnabled", inputs.OfflineSessionMaxLifespanEnabled != nil && !ptrBoolEqual(state.OfflineSessionMaxLifespanEnabled, inputs.OfflineSessionMaxLifespanEnabled))
+	changed("offlineSessionMaxLifespan", inputs.OfflineSessionMaxLifespan != nil && !ptrIntEqual(state.OfflineSessionMaxLifespan, inputs.OfflineSessionMaxLifespan))
+	changed("actionTokenGeneratedByUserLifespan", inputs.ActionTokenGeneratedByUserLifespan != nil && !ptrIntEqual(state.ActionTokenGeneratedByUserLifespan, inputs.ActionTokenGeneratedByUserLifespan))
+	changed("actionTokenGeneratedByAdminLifespan", inputs.ActionTokenGeneratedByAdminLifespan != nil && !ptrIntEqual(state.ActionTokenGeneratedByAdminLifespan, inputs.ActionTokenGeneratedByAdminLifespan))
+	changed("userManagedAccessAllowed", inputs.UserManagedAccessAllowed != nil && !ptrBoolEqual(state.UserManagedAccessAllowed, inputs.UserManagedAccessAllowed))
+	changed("defaultSignatureAlgorithm", inputs.DefaultSignatureAlgorithm != nil && !realmStringEqual(state.DefaultSignatureAlgorithm, inputs.DefaultSignatureAlgorithm))
+	changed("actionTokenLifespanOverrides", inputs.ActionTokenLifespanOverrides != nil && !lifespanOverridesContained(inputs.ActionTokenLifespanOverrides, state.ActionTokenLifespanOverrides))
+	changed("attributes", inputs.Attributes != nil && !attributesContained(inputs.Attributes, state.Attributes))
+
+	// Full management also removes what was dropped from the inputs
+	if full {
+		changed("smtpServer", inputs.manages("smtpServer") && inputs.SmtpServer == nil && state.SmtpServer != nil)
+		changed("actionTokenLifespanOverrides", inputs.manages("actionTokenLifespanOverrides") &&
+			!maps.Equal(inputs.ActionTokenLifespanOverrides, state.ActionTokenLifespanOverrides))
+		changed("attributes", len(inputs.staleAttributes(state.Attributes, state.Attributes)) > 0)
+	}
+
+	// Without a refresh first, the state does not tell about changes made in the admin console
+	if _, replace := diff["name"]; !replace && ptrBoolValue(req.Inputs.WarnOnDrift) && state.DriftBaseline != nil {
+		checkDrift(ctx, config, req.Inputs, state.DriftBaseline)
+	}
+
+	_, replace := diff["name"]
+	return infer.DiffResponse{
+		HasChanges:          len(diff) > 0,
+		DetailedDiff:        diff,
+		DeleteBeforeReplace: replace,
+	}, nil
+}
 
-	if req.Inputs.DisplayName != nil && !ptrStringEqual(req.State.DisplayName, req.Inputs.DisplayName) {
-		hasChanges = true
-	}
+var (
+	realmLocksMu sync.Mutex
+	realmLocks   = map[string]*sync.Mutex{}
+)
 
-	if req.Inputs.DisplayNameHtml != nil && !ptrStringEqual(req.State.DisplayNameHtml, req.Inputs.DisplayNameHtml) {
-		hasChanges = true
+// lockRealm serializes the updates of a realm's representation within the provider process and returns the unlock
+// function. Realm and RealmWebAuthnPasswordlessPolicy each read the whole representation, change their fields and
+// write it back, so when the engine runs them in parallel, the later write would revert the fields of the earlier one
+func lockRealm(name string) func() {
+	realmLocksMu.Lock()
+	lock, ok := realmLocks[name]
+	if !ok {
+		lock = &sync.Mutex{}
+		realmLocks[name] = lock
 	}
+	realmLocksMu.Unlock()
 
-	if req.Inputs.LoginTheme != nil && !ptrStringEqual(req.State.LoginTheme, req.Inputs.LoginTheme) {
-		hasChanges = true
-	}
+	lock.Lock()
+	return lock.Unlock
+}
 
-	if req.Inputs.AccountTheme != nil && !ptrStringEqual(req.State.AccountTheme, req.Inputs.AccountTheme) {
-		hasChanges = true
-	}
+// updateManagedFields updates only the fields managed by this provider
+// In full management mode, it also clears the SMTP server when unset and
+// removes the stale attributes, given the previously managed ones
+func updateManagedFields(ctx context.Context, client KeycloakClient, token string, args RealmArgs, full bool, previous map[string]string) error {
+	defer lockRealm(args.Name)()
 
-	if req.Inputs.AdminTheme != nil && !ptrStringEqual(req.State.AdminTheme, req.Inputs.AdminTheme) {
-		hasChanges = true
+	currentRealm, err := client.GetRealm(ctx, token, args.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get current realm: %w", err)
 	}
 
-	if req.Inputs.EmailTheme != nil && !ptrStringEqual(req.State.EmailTheme, req.Inputs.EmailTheme) {
-		hasChanges = true
+	updateRealm, hasChanges := reconcileRealm(currentRealm, args, full, previous)
+	if !hasChanges {
+		return nil
 	}
 
-	if req.Inputs.SmtpServer != nil {
-		smtpConfig := convertSmtpConfig(req.Inputs.SmtpServer)
-		stateSmtpConfig := convertSmtpConfig(req.State.SmtpServer)
-		if !smtpConfigEqual(&smtpConfig, &stateSmtpConfig) {
-			hasChanges = true
-		}
+	err = client.UpdateRealm(ctx, token, updateRealm)
+	if err != nil {
+		return fmt.Errorf("failed to update realm: %w", err)
 	}
 
-	return infer.DiffResponse{
-		HasChanges: hasChanges,
-	}, nil
+	return nil
 }
 
-// updateManagedFields updates only the fields managed by this provider
-func updateManagedFields(ctx context.Context, client *gocloak.GoCloak, token string, args RealmArgs) error {
-	currentRealm, err := client.GetRealm(ctx, token, args.Name)
-	if err != nil {
-		return fmt.Errorf("failed to get current realm: %w", err)
-	}
-
+// reconcileRealm applies the managed fields of the inputs to the current realm, and reports whether any changed
+func reconcileRealm(currentRealm *gocloak.RealmRepresentation, args RealmArgs, full bool, previous map[string]string) (gocloak.RealmRepresentation, bool) {
 	// Track if any managed field has changed
 	hasChanges := false
 
@@ -373,62 +901,129 @@ func updateManagedFields(ctx context.Context, client *gocloak.GoCloak, token str
 		hasChanges = true
 	}
 
-	if args.DisplayName != nil && !ptrStringEqual(currentRealm.DisplayName, args.DisplayName) {
+	if args.DisplayName != nil && !realmStringEqual(currentRealm.DisplayName, args.DisplayName) {
 		updateRealm.DisplayName = args.DisplayName
 		hasChanges = true
 	}
 
-	if args.DisplayNameHtml != nil && !ptrStringEqual(currentRealm.DisplayNameHTML, args.DisplayNameHtml) {
+	if args.DisplayNameHtml != nil && !realmStringEqual(currentRealm.DisplayNameHTML, args.DisplayNameHtml) {
 		updateRealm.DisplayNameHTML = args.DisplayNameHtml
 		hasChanges = true
 	}
 
-	if args.LoginTheme != nil && !ptrStringEqual(currentRealm.LoginTheme, args.LoginTheme) {
+	if args.LoginTheme != nil && !realmStringEqual(currentRealm.LoginTheme, args.LoginTheme) {
 		updateRealm.LoginTheme = args.LoginTheme
 		hasChanges = true
 	}
 
-	if args.AccountTheme != nil && !ptrStringEqual(currentRealm.AccountTheme, args.AccountTheme) {
+	if args.AccountTheme != nil && !realmStringEqual(currentRealm.AccountTheme, args.AccountTheme) {
 		updateRealm.AccountTheme = args.AccountTheme
 		hasChanges = true
 	}
 
-	if args.AdminTheme != nil && !ptrStringEqual(currentRealm.AdminTheme, args.AdminTheme) {
+	if args.AdminTheme != nil && !realmStringEqual(currentRealm.AdminTheme, args.AdminTheme) {
 		updateRealm.AdminTheme = args.AdminTheme
 		hasChanges = true
 	}
 
-	if args.EmailTheme != nil && !ptrStringEqual(currentRealm.EmailTheme, args.EmailTheme) {
+	if args.EmailTheme != nil && !realmStringEqual(currentRealm.EmailTheme, args.EmailTheme) {
 		updateRealm.EmailTheme = args.EmailTheme
 		hasChanges = true
 	}
 
 	if args.SmtpServer != nil {
+		// Compared as in Diff, since Keycloak returns the password masked
+		var current map[string]string
+		if currentRealm.SMTPServer != nil {
+			current = *currentRealm.SMTPServer
+		}
 		smtpConfig := convertSmtpConfig(args.SmtpServer)
-		if !smtpConfigEqual(currentRealm.SMTPServer, &smtpConfig) {
+		if !smtpConfigContained(smtpConfig, current) {
 			updateRealm.SMTPServer = &smtpConfig
 			hasChanges = true
 		}
 	}
 
-	if !hasChanges {
-		return nil
+	if args.RevokeRefreshToken != nil && !ptrBoolEqual(currentRealm.RevokeRefreshToken, args.RevokeRefreshToken) {
+		updateRealm.RevokeRefreshToken = args.RevokeRefreshToken
+		hasChanges = true
 	}
 
-	err = client.UpdateRealm(ctx, token, updateRealm)
-	if err != nil {
-		return fmt.Errorf("failed to update realm: %w", err)
+	if args.RefreshTokenMaxReuse != nil && !ptrIntEqual(currentRealm.RefreshTokenMaxReuse, args.RefreshTokenMaxReuse) {
+		updateRealm.RefreshTokenMaxReuse = args.RefreshTokenMaxReuse
+		hasChanges = true
 	}
 
-	return nil
+	if args.OfflineSessionIdleTimeout != nil && !ptrIntEqual(currentRealm.OfflineSessionIdleTimeout, args.OfflineSessionIdleTimeout) {
+		updateRealm.OfflineSessionIdleTimeout = args.OfflineSessionIdleTimeout
+		hasChanges = true
+	}
+
+	if args.OfflineSessionMaxLifespanEnabled != nil && !ptrBoolEqual(currentRealm.OfflineSessionMaxLifespanEnabled, args.OfflineSessionMaxLifespanEnabled) {
+		updateRealm.OfflineSessionMaxLifespanEnabled = args.OfflineSessionMaxLifespanEnabled
+		hasChanges = true
+	}
+
+	if args.OfflineSessionMaxLifespan != nil && !ptrIntEqual(currentRealm.OfflineSessionMaxLifespan, args.OfflineSessionMaxLifespan) {
+		updateRealm.OfflineSessionMaxLifespan = args.OfflineSessionMaxLifespan
+		hasChanges = true
+	}
+
+	if args.ActionTokenGeneratedByUserLifespan != nil && !ptrIntEqual(currentRealm.ActionTokenGeneratedByUserLifespan, args.ActionTokenGeneratedByUserLifespan) {
+		updateRealm.ActionTokenGeneratedByUserLifespan = args.ActionTokenGeneratedByUserLifespan
+		hasChanges = true
+	}
+
+	if args.ActionTokenGeneratedByAdminLifespan != nil && !ptrIntEqual(currentRealm.ActionTokenGeneratedByAdminLifespan, args.ActionTokenGeneratedByAdminLifespan) {
+		updateRealm.ActionTokenGeneratedByAdminLifespan = args.ActionTokenGeneratedByAdminLifespan
+		hasChanges = true
+	}
+
+	if args.UserManagedAccessAllowed != nil && !ptrBoolEqual(currentRealm.UserManagedAccessAllowed, args.UserManagedAccessAllowed) {
+		updateRealm.UserManagedAccessAllowed = args.UserManagedAccessAllowed
+		hasChanges = true
+	}
+
+	if args.DefaultSignatureAlgorithm != nil && !realmStringEqual(currentRealm.DefaultSignatureAlgorithm, args.DefaultSignatureAlgorithm) {
+		updateRealm.DefaultSignatureAlgorithm = args.DefaultSignatureAlgorithm
+		hasChanges = true
+	}
+
+	if full && args.manages("smtpServer") && args.SmtpServer == nil && currentRealm.SMTPServer != nil && len(*currentRealm.SMTPServer) > 0 {
+		updateRealm.SMTPServer = &map[string]string{}
+		hasChanges = true
+	}
+
+	var current map[string]string
+	if currentRealm.Attributes != nil {
+		current = *currentRealm.Attributes
+	}
+	managed := args.realmAttributes()
+	var stale []string
+	if full {
+		stale = args.staleAttributes(current, previous)
+	}
+	if (managed != nil && !attributesContained(managed, current)) || len(stale) > 0 {
+		attributes := mergeAttributes(current, managed)
+		for _, key := range stale {
+			delete(attributes, key)
+		}
+		updateRealm.Attributes = &attributes
+		hasChanges = true
+	}
+
+	return updateRealm, hasChanges
 }
 
-func readRealmState(ctx context.Context, client *gocloak.GoCloak, token, realmName string) (RealmState, error) {
+func readRealmState(ctx context.Context, client KeycloakClient, token, realmName string) (RealmState, error) {
 	realm, err := client.GetRealm(ctx, token, realmName)
 	if err != nil {
 		return RealmState{}, fmt.Errorf("failed to get realm: %w", err)
 	}
+	return realmState(realm), nil
+}
 
+func realmState(realm *gocloak.RealmRepresentation) RealmState {
 	state := RealmState{
 		ID:   *realm.Realm,
 		Name: *realm.Realm,
@@ -467,14 +1062,78 @@ func readRealmState(ctx context.Context, client *gocloak.GoCloak, token, realmNa
 		state.SmtpServer = convertFromKeycloakSmtp(*realm.SMTPServer)
 	}
 
+	if realm.RevokeRefreshToken != nil {
+		state.RevokeRefreshToken = realm.RevokeRefreshToken
+	}
+
+	if realm.RefreshTokenMaxReuse != nil {
+		state.RefreshTokenMaxReuse = realm.RefreshTokenMaxReuse
+	}
+
+	if realm.OfflineSessionIdleTimeout != nil {
+		state.OfflineSessionIdleTimeout = realm.OfflineSessionIdleTimeout
+	}
+
+	if realm.OfflineSessionMaxLifespanEnabled != nil {
+		state.OfflineSessionMaxLifespanEnabled = realm.OfflineSessionMaxLifespanEnabled
+	}
+
+	if realm.OfflineSessionMaxLifespan != nil {
+		state.OfflineSessionMaxLifespan = realm.OfflineSessionMaxLifespan
+	}
+
+	if realm.ActionTokenGeneratedByUserLifespan != nil {
+		state.ActionTokenGeneratedByUserLifespan = realm.ActionTokenGeneratedByUserLifespan
+	}
+
+	if realm.ActionTokenGeneratedByAdminLifespan != nil {
+		state.ActionTokenGeneratedByAdminLifespan = realm.ActionTokenGeneratedByAdminLifespan
+	}
+
+	if realm.UserManagedAccessAllowed != nil {
+		state.UserManagedAccessAllowed = realm.UserManagedAccessAllowed
+	}
+
+	if realm.DefaultSignatureAlgorithm != nil {
+		state.DefaultSignatureAlgorithm = realm.DefaultSignatureAlgorithm
+	}
+
+	if realm.Attributes != nil {
+		state.Attributes = *realm.Attributes
+		state.ActionTokenLifespanOverrides = lifespanOverridesFromAttributes(*realm.Attributes)
+	}
+
+	return state
+}
+
+// previewRealm predicts the state of a realm after an update with the given inputs, by applying them to the live
+// realm the way updateManagedFields does. A realm that does not exist yet is previewed from the inputs alone
+func previewRealm(ctx context.Context, client KeycloakClient, token string, inputs, args RealmArgs, full bool, previous map[string]string) (RealmState, error) {
+	// The name is unknown while it depends on resources that are not created yet
+	if inputs.Name == "" {
+		return inputs.previewState(), nil
+	}
+
+	current, err := client.GetRealm(ctx, token, inputs.Name)
+	if isNotFound(err) {
+		return inputs.previewState(), nil
+	}
+	if err != nil {
+		return RealmState{}, fmt.Errorf("failed to get realm: %w", err)
+	}
+
+	realm, _ := reconcileRealm(current, args, full, previous)
+	state := realmState(&realm)
+	state.Attributes = managedAttributes(state.Attributes, inputs.Attributes)
+	state.DeletionProtection = inputs.DeletionProtection
 	return state, nil
 }
 
-func realmExistsWithClient(ctx context.Context, client *gocloak.GoCloak, token, realmName string) (bool, error) {
+func realmExistsWithClient(ctx context.Context, client KeycloakClient, token, realmName string) (bool, error) {
 	_, err := client.GetRealm(ctx, token, realmName)
 	if err != nil {
 		// If it's a 404-like error, realm doesn't exist
-		if err.Error() == "404" || err.Error() == "realm not found" {
+		if isNotFound(err) {
 			return false, nil
 		}
 		return false, err
@@ -599,6 +1258,16 @@ func ptrStringEqual(a, b *string) bool {
 	return *a == *b
 }
 
+// realmStringEqual compares realm string fields, which Keycloak treats the same whether empty or unset
+func realmStringEqual(a, b *string) bool {
+	return gocloak.PString(a) == gocloak.PString(b)
+}
+
+// ptrBoolValue treats an unset flag as false
+func ptrBoolValue(b *bool) bool {
+	return b != nil && *b
+}
+
 func ptrBoolEqual(a, b *bool) bool {
 	if a == nil && b == nil {
 		return true
@@ -609,18 +1278,107 @@ func ptrBoolEqual(a, b *bool) bool {
 	return *a == *b
 }
 
-func smtpConfigEqual(a *map[string]string, b *map[string]string) bool {
+func ptrIntEqual(a, b *int) bool {
 	if a == nil && b == nil {
 		return true
 	}
 	if a == nil || b == nil {
 		return false
 	}
-	if len(*a) != len(*b) {
-		return false
+	return *a == *b
+}
+
+// actionTokenLifespanPrefix prefixes the realm attributes holding per-action token lifespans
+const actionTokenLifespanPrefix = "actionTokenGeneratedByUserLifespan."
+
+// realmAttributes returns the custom attributes combined with the per-action token lifespan overrides
+func (args RealmArgs) realmAttributes() map[string]string {
+	if args.Attributes == nil && args.ActionTokenLifespanOverrides == nil {
+		return nil
+	}
+	attributes := mergeAttributes(nil, args.Attributes)
+	for action, lifespan := range args.ActionTokenLifespanOverrides {
+		attributes[actionTokenLifespanPrefix+action] = strconv.Itoa(lifespan)
+	}
+	return attributes
+}
+
+// lifespanOverridesFromAttributes extracts the per-action token lifespans from realm attributes
+func lifespanOverridesFromAttributes(attributes map[string]string) map[string]int {
+	var overrides map[string]int
+	for key, value := range attributes {
+		action, ok := strings.CutPrefix(key, actionTokenLifespanPrefix)
+		if !ok {
+			continue
+		}
+		if lifespan := parseInt(value); lifespan != nil {
+			if overrides == nil {
+				overrides = make(map[string]int)
+			}
+			overrides[action] = *lifespan
+		}
+	}
+	return overrides
+}
+
+// lifespanOverridesContained reports whether every managed override has the same value in actual
+func lifespanOverridesContained(managed, actual map[string]int) bool {
+	for action, lifespan := range managed {
+		if actualLifespan, ok := actual[action]; !ok || actualLifespan != lifespan {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeAttributes returns the existing attributes overlaid with the managed ones
+func mergeAttributes(existing, managed map[string]string) map[string]string {
+	result := make(map[string]string, len(existing)+len(managed))
+	for k, v := range existing {
+		result[k] = v
+	}
+	for k, v := range managed {
+		result[k] = v
 	}
-	for k, v := range *a {
-		if bv, ok := (*b)[k]; !ok || bv != v {
+	return result
+}
+
+// managedAttributes filters attributes down to the keys present in managed
+func managedAttributes[V any](attributes, managed map[string]V) map[string]V {
+	if managed == nil {
+		return nil
+	}
+	result := make(map[string]V, len(managed))
+	for k := range managed {
+		if v, ok := attributes[k]; ok {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// attributesContained reports whether every managed attribute has the same value in actual
+func attributesContained(managed, actual map[string]string) bool {
+	for k, v := range managed {
+		if av, ok := actual[k]; !ok || av != v {
+			return false
+		}
+	}
+	return true
+}
+
+// maskedSmtpPassword is returned by Keycloak in place of the SMTP password
+const maskedSmtpPassword = "**********"
+
+// smtpConfigContained reports whether every managed SMTP setting has the same value in actual. A masked
+// password read back from Keycloak cannot be compared and is treated as unchanged
+func smtpConfigContained(managed, actual map[string]string) bool {
+	for k, v := range managed {
+		av, ok := actual[k]
+		if k == "password" && av == maskedSmtpPassword {
+			continue
+		}
+		if !ok || av != v {
 			return false
 		}
 	}
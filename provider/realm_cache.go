@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+)
+
+// realmCacheTTL is how long a fetched realm is reused. Within a deployment, many resources of one realm read it
+// in quick succession, while changes made in the admin console meanwhile are picked up by the next one
+const realmCacheTTL = 10 * time.Second
+
+// realmCache keeps recently fetched realm representations by name. Any write to a realm through the admin API
+// drops its entry, see invalidateOnWrites, so that the merge of a later update never starts from a stale realm
+type realmCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cachedRealm
+	// seq counts the writes to all realms, so that a fetch which raced a write is not cached. The entry of a written
+	// realm keeps the count of its last write until it expires, and floor the highest count of the expired entries
+	// and of the writes to all realms
+	seq   uint64
+	floor uint64
+}
+
+// cachedRealm is a fetched realm, or without a realm the record of a write
+type cachedRealm struct {
+	realm   []byte
+	updated time.Time
+	written uint64
+}
+
+func newRealmCache(ttl time.Duration) *realmCache {
+	return &realmCache{ttl: ttl, entries: map[string]cachedRealm{}}
+}
+
+// get returns a copy of a cached realm, which callers may modify, along with the count a fetch must be stored with
+// when the realm is not cached
+func (cache *realmCache) get(name string) (*gocloak.RealmRepresentation, uint64) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry, ok := cache.entries[name]
+	if !ok || entry.realm == nil || time.Since(entry.updated) > cache.ttl {
+		return nil, cache.seq
+	}
+
+	var realm gocloak.RealmRepresentation
+	if err := json.Unmarshal(entry.realm, &realm); err != nil {
+		return nil, cache.seq
+	}
+	return &realm, cache.seq
+}
+
+// put caches a realm fetched at the given count, unless it was written to since
+func (cache *realmCache) put(name string, seq uint64, realm *gocloak.RealmRepresentation) {
+	encoded, err := json.Marshal(realm)
+	if err != nil {
+		return
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.prune()
+	entry := cache.entries[name]
+	if seq < cache.floor || seq < entry.written {
+		return
+	}
+	cache.entries[name] = cachedRealm{realm: encoded, updated: time.Now(), written: entry.written}
+}
+
+// invalidate drops a realm, or every realm when the name is empty
+func (cache *realmCache) invalidate(name string) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.seq++
+	if name == "" {
+		cache.floor = cache.seq
+		clear(cache.entries)
+		return
+	}
+	cache.entries[name] = cachedRealm{updated: time.Now(), written: cache.seq}
+	cache.prune()
+}
+
+// prune drops expired entries. The floor takes over the writes they recorded, so fetches that started before are
+// still not cached
+func (cache *realmCache) prune() {
+	for name, entry := range cache.entries {
+		if time.Since(entry.updated) > cache.ttl {
+			cache.floor = max(cache.floor, entry.written)
+			delete(cache.entries, name)
+		}
+	}
+}
+
+// invalidateOnWrites drops the cached realm a request other than a GET is sent for, before it is sent and again
+// when it is done, so that fetches running meanwhile are not cached either. Writes to the realm list drop all realms
+func (cache *realmCache) invalidateOnWrites(client *gocloak.GoCloak) {
+	httpClient := client.RestyClient().GetClient()
+	httpClient.Transport = &realmInvalidatingTransport{base: httpClient.Transport, cache: cache}
+}
+
+type realmInvalidatingTransport struct {
+	base  http.RoundTripper
+	cache *realmCache
+}
+
+func (t *realmInvalidatingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return t.base.RoundTrip(req)
+	}
+
+	_, path, ok := strings.Cut(req.URL.Path, "/admin/realms")
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+	realm, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+
+	t.cache.invalidate(realm)
+	defer t.cache.invalidate(realm)
+	return t.base.RoundTrip(req)
+}
+
+// realmCachingClient serves GetRealm from a realmCache
+type realmCachingClient struct {
+	KeycloakClient
+	cache *realmCache
+}
+
+func (client realmCachingClient) GetRealm(ctx context.Context, token, realm string) (*gocloak.RealmRepresentation, error) {
+	cached, seq := client.cache.get(realm)
+	if cached != nil {
+		return cached, nil
+	}
+
+	fetched, err := client.KeycloakClient.GetRealm(ctx, token, realm)
+	if err != nil {
+		return nil, err
+	}
+	client.cache.put(realm, seq, fetched)
+	return fetched, nil
+}
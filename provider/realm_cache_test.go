@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+)
+
+func TestRealmCacheSkipsFetchesThatRacedAWrite(t *testing.T) {
+	cache := newRealmCache(time.Minute)
+	realm := &gocloak.RealmRepresentation{Realm: gocloak.StringP("acme")}
+
+	_, seq := cache.get("acme")
+	cache.invalidate("acme")
+	cache.put("acme", seq, realm)
+	if cached, _ := cache.get("acme"); cached != nil {
+		t.Error("a fetch that started before a write was cached")
+	}
+
+	_, seq = cache.get("acme")
+	cache.invalidate("beta")
+	cache.put("acme", seq, realm)
+	if cached, _ := cache.get("acme"); cached == nil {
+		t.Error("a write to another realm kept a fetch from being cached")
+	}
+
+	_, seq = cache.get("acme")
+	cache.invalidate("")
+	cache.put("acme", seq, realm)
+	if cached, _ := cache.get("acme"); cached != nil {
+		t.Error("a fetch that started before a write to all realms was cached")
+	}
+}
+
+func TestRealmCachePrunesExpiredWrites(t *testing.T) {
+	cache := newRealmCache(time.Millisecond)
+	realm := &gocloak.RealmRepresentation{Realm: gocloak.StringP("acme")}
+
+	_, seq := cache.get("acme")
+	cache.invalidate("acme")
+	for i := range 100 {
+		cache.invalidate(fmt.Sprintf("realm-%d", i))
+	}
+	time.Sleep(5 * time.Millisecond)
+	cache.invalidate("beta")
+
+	if len(cache.entries) != 1 {
+		t.Errorf("want only the last write recorded, got %d entries", len(cache.entries))
+	}
+	cache.put("acme", seq, realm)
+	if _, ok := cache.entries["acme"]; ok {
+		t.Error("a fetch that started before a pruned write was cached")
+	}
+}
@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	p "github.com/pulumi/pulumi-go-provider"
+)
+
+// driftAttributePrefix prefixes the realm attributes in a drift baseline, which are keyed like the realm settings
+const driftAttributePrefix = "attributes."
+
+// recordDriftBaseline stores the live values of the realm settings and attributes the inputs leave unmanaged in the
+// state, when warnOnDrift is set. Given the baseline of the previous state, as on an update, it keeps the values
+// recorded there for settings that stay unmanaged, so that changes made in the admin console since are still reported
+// by the next refresh instead of being absorbed into the baseline
+func recordDriftBaseline(ctx context.Context, client KeycloakClient, token string, config ProviderConfig, args RealmArgs, previous map[string]string, state *RealmState) error {
+	if args.WarnOnDrift == nil || !*args.WarnOnDrift {
+		return nil
+	}
+
+	realm, err := client.GetRealm(ctx, token, args.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get realm: %w", err)
+	}
+
+	baseline, err := driftSnapshot(realm, config, args)
+	if err != nil {
+		return err
+	}
+	if previous != nil {
+		unmanaged, err := driftUnmanaged(config, args)
+		if err != nil {
+			return err
+		}
+		for key, value := range previous {
+			if unmanaged(key) {
+				baseline[key] = value
+			}
+		}
+	}
+	state.DriftBaseline = baseline
+	return nil
+}
+
+// driftSnapshot renders the unmanaged settings of a realm as JSON values keyed by setting name, and its unmanaged
+// attributes keyed by attributes.<name>
+func driftSnapshot(realm *gocloak.RealmRepresentation, config ProviderConfig, args RealmArgs) (map[string]string, error) {
+	unmanaged, err := driftUnmanaged(config, args)
+	if err != nil {
+		return nil, err
+	}
+
+	live, err := jsonFields(realm)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := map[string]string{}
+	for key, value := range live {
+		if key != "attributes" && unmanaged(key) {
+			snapshot[key] = string(value)
+		}
+	}
+	if realm.Attributes != nil {
+		for key, value := range *realm.Attributes {
+			if unmanaged(driftAttributePrefix + key) {
+				snapshot[driftAttributePrefix+key] = value
+			}
+		}
+	}
+
+	return snapshot, nil
+}
+
+// driftUnmanaged returns whether a drift baseline key names a setting or attribute the inputs leave unmanaged
+func driftUnmanaged(config ProviderConfig, args RealmArgs) (func(key string) bool, error) {
+	effective, full := args.reconciledArgs(config)
+
+	// The settings sent to Keycloak are exactly the managed ones
+	managed, err := jsonFields(effective.toKeycloakRealm())
+	if err != nil {
+		return nil, err
+	}
+	if effective.Enabled == nil {
+		delete(managed, "enabled")
+	}
+	if full && effective.manages("smtpServer") {
+		managed["smtpServer"] = nil
+	}
+	managedAttributes := effective.realmAttributes()
+
+	return func(key string) bool {
+		if attribute, ok := strings.CutPrefix(key, driftAttributePrefix); ok {
+			if _, ok := managedAttributes[attribute]; ok {
+				return false
+			}
+			return !full || !effective.manages("actionTokenLifespanOverrides") || !strings.HasPrefix(attribute, actionTokenLifespanPrefix)
+		}
+		_, ok := managed[key]
+		return !ok && key != "id" && key != "realm" && key != "attributes"
+	}, nil
+}
+
+// checkDrift fetches a realm and warns about the unmanaged settings and attributes that changed since the baseline
+// in its state was recorded. Failures only skip the check, which must not keep a preview from running
+func checkDrift(ctx context.Context, config ProviderConfig, args RealmArgs, baseline map[string]string) {
+	client, token, err := login(ctx)
+	if err != nil {
+		p.GetLogger(ctx).Debugf("cannot check realm %q for drift: %v", args.Name, err)
+		return
+	}
+	realm, err := client.GetRealm(ctx, token, args.Name)
+	if err != nil {
+		p.GetLogger(ctx).Debugf("cannot check realm %q for drift: %v", args.Name, err)
+		return
+	}
+	current, err := driftSnapshot(realm, config, args)
+	if err != nil {
+		p.GetLogger(ctx).Debugf("cannot check realm %q for drift: %v", args.Name, err)
+		return
+	}
+	reportDrift(ctx, args.Name, baseline, current)
+}
+
+func jsonFields(value interface{}) (map[string]json.RawMessage, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode realm: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode realm: %w", err)
+	}
+	return fields, nil
+}
+
+// reportDrift warns about the unmanaged settings and attributes that changed since the baseline was recorded.
+// Nothing is reported without a previous baseline
+func reportDrift(ctx context.Context, realmName string, previous, current map[string]string) {
+	if previous == nil || current == nil {
+		return
+	}
+
+	var drifted []string
+	for key, value := range current {
+		if old, ok := previous[key]; !ok || old != value {
+			drifted = append(drifted, key)
+		}
+	}
+	for key := range previous {
+		if _, ok := current[key]; !ok {
+			drifted = append(drifted, key)
+		}
+	}
+	if len(drifted) == 0 {
+		return
+	}
+
+	slices.Sort(drifted)
+	p.GetLogger(ctx).Warningf("realm %q has unmanaged settings changed outside of Pulumi: %s", realmName, strings.Join(drifted, ", "))
+	for _, key := range drifted {
+		p.GetLogger(ctx).Debugf("realm %q drift in %s: %q -> %q", realmName, key, previous[key], current[key])
+	}
+}
@@ -0,0 +1,189 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// RealmLocalization manages message bundle overrides of a realm for a single locale.
+// Only the listed keys are managed; translations added elsewhere are preserved.
+type RealmLocalization struct{}
+
+type RealmLocalizationArgs struct {
+	RealmID string            `pulumi:"realmId" provider:"replaceOnChanges"`
+	Locale  string            `pulumi:"locale" provider:"replaceOnChanges"`
+	Texts   map[string]string `pulumi:"texts"`
+}
+
+type RealmLocalizationState struct {
+	RealmLocalizationArgs
+}
+
+// Annotate provides schema documentation for the RealmLocalization resource
+func (r *RealmLocalization) Annotate(a infer.Annotator) {
+	a.Describe(&r, "Realm message bundle overrides for one locale. Keys not listed in texts are left untouched")
+}
+
+func (args *RealmLocalizationArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.Locale, "The locale the texts apply to, e.g. en or de")
+	a.Describe(&args.Texts, "Message keys and their translated texts")
+}
+
+func (r *RealmLocalization) Create(ctx context.Context, req infer.CreateRequest[RealmLocalizationArgs]) (infer.CreateResponse[RealmLocalizationState], error) {
+	id := req.Inputs.RealmID + "/" + req.Inputs.Locale
+
+	if req.DryRun {
+		return infer.CreateResponse[RealmLocalizationState]{
+			ID:     id,
+			Output: RealmLocalizationState{req.Inputs},
+		}, nil
+	}
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.CreateResponse[RealmLocalizationState]{}, err
+	}
+
+	state, err := syncRealmLocalization(ctx, client, token, req.Inputs, nil)
+	if err != nil {
+		return infer.CreateResponse[RealmLocalizationState]{}, err
+	}
+
+	return infer.CreateResponse[RealmLocalizationState]{
+		ID:     id,
+		Output: state,
+	}, nil
+}
+
+func (r *RealmLocalization) Update(ctx context.Context, req infer.UpdateRequest[RealmLocalizationArgs, RealmLocalizationState]) (infer.UpdateResponse[RealmLocalizationState], error) {
+	if req.DryRun {
+		return infer.UpdateResponse[RealmLocalizationState]{
+			Output: RealmLocalizationState{req.Inputs},
+		}, nil
+	}
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.UpdateResponse[RealmLocalizationState]{}, err
+	}
+
+	state, err := syncRealmLocalization(ctx, client, token, req.Inputs, req.State.Texts)
+	if err != nil {
+		return infer.UpdateResponse[RealmLocalizationState]{}, err
+	}
+
+	return infer.UpdateResponse[RealmLocalizationState]{
+		Output: state,
+	}, nil
+}
+
+func (r *RealmLocalization) Delete(ctx context.Context, req infer.DeleteRequest[RealmLocalizationState]) (infer.DeleteResponse, error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
+	for key := range req.State.Texts {
+		resp, err := client.GetRequestWithBearerAuth(ctx, token).
+			Delete(adminRealmURL(ctx, req.State.RealmID, "localization", req.State.Locale, key))
+		if err := checkResponse(resp, err); err != nil && !isNotFound(err) {
+			return infer.DeleteResponse{}, fmt.Errorf("failed to delete localization text %s: %w", key, err)
+		}
+	}
+
+	return infer.DeleteResponse{}, nil
+}
+
+func (r *RealmLocalization) Read(ctx context.Context, req infer.ReadRequest[RealmLocalizationArgs, RealmLocalizationState]) (infer.ReadResponse[RealmLocalizationArgs, RealmLocalizationState], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.ReadResponse[RealmLocalizationArgs, RealmLocalizationState]{}, err
+	}
+
+	parts, err := parseResourceID(req.ID, "<realm>/<locale>")
+	if err != nil {
+		return infer.ReadResponse[RealmLocalizationArgs, RealmLocalizationState]{}, err
+	}
+	realmName, locale := parts[0], parts[1]
+
+	texts, err := getRealmLocalizationTexts(ctx, client, token, realmName, locale)
+	if err != nil {
+		if isNotFound(err) {
+			return infer.ReadResponse[RealmLocalizationArgs, RealmLocalizationState]{}, nil
+		}
+		return infer.ReadResponse[RealmLocalizationArgs, RealmLocalizationState]{}, err
+	}
+
+	// An import manages all current texts, otherwise only the managed ones are refreshed
+	managed := req.State.Texts
+	if req.State.RealmID == "" {
+		managed = texts
+	}
+
+	state := RealmLocalizationState{
+		RealmLocalizationArgs{
+			RealmID: realmName,
+			Locale:  locale,
+			Texts:   map[string]string{},
+		},
+	}
+	for key := range managed {
+		if text, ok := texts[key]; ok {
+			state.Texts[key] = text
+		}
+	}
+
+	return infer.ReadResponse[RealmLocalizationArgs, RealmLocalizationState]{
+		ID:     req.ID,
+		Inputs: state.RealmLocalizationArgs,
+		State:  state,
+	}, nil
+}
+
+// syncRealmLocalization writes changed texts and deletes keys that were previously managed but are no longer listed
+func syncRealmLocalization(ctx context.Context, client KeycloakClient, token string, args RealmLocalizationArgs, previous map[string]string) (RealmLocalizationState, error) {
+	current, err := getRealmLocalizationTexts(ctx, client, token, args.RealmID, args.Locale)
+	if err != nil {
+		return RealmLocalizationState{}, err
+	}
+
+	for key, text := range args.Texts {
+		if existing, ok := current[key]; ok && existing == text {
+			continue
+		}
+		resp, err := client.GetRequestWithBearerAuth(ctx, token).
+			SetHeader("Content-Type", "text/plain").
+			SetBody(text).
+			Put(adminRealmURL(ctx, args.RealmID, "localization", args.Locale, key))
+		if err := checkResponse(resp, err); err != nil {
+			return RealmLocalizationState{}, fmt.Errorf("failed to set localization text %s: %w", key, err)
+		}
+	}
+
+	for key := range previous {
+		if _, ok := args.Texts[key]; ok {
+			continue
+		}
+		resp, err := client.GetRequestWithBearerAuth(ctx, token).
+			Delete(adminRealmURL(ctx, args.RealmID, "localization", args.Locale, key))
+		if err := checkResponse(resp, err); err != nil && !isNotFound(err) {
+			return RealmLocalizationState{}, fmt.Errorf("failed to delete localization text %s: %w", key, err)
+		}
+	}
+
+	return RealmLocalizationState{args}, nil
+}
+
+func getRealmLocalizationTexts(ctx context.Context, client KeycloakClient, token, realmName, locale string) (map[string]string, error) {
+	texts := map[string]string{}
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetResult(&texts).
+		Get(adminRealmURL(ctx, realmName, "localization", locale))
+	if err := checkResponse(resp, err); err != nil {
+		return nil, fmt.Errorf("failed to get localization texts: %w", err)
+	}
+	return texts, nil
+}
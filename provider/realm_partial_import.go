@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// RealmPartialImport applies a partial realm export (users, clients, groups, roles, ...) to an existing realm.
+// Imported objects are not tracked individually and are left in place when the resource is destroyed.
+type RealmPartialImport struct{}
+
+type RealmPartialImportArgs struct {
+	RealmID          string `pulumi:"realmId" provider:"replaceOnChanges"`
+	Content          string `pulumi:"content" provider:"secret"`
+	IfResourceExists string `pulumi:"ifResourceExists,optional"`
+}
+
+type RealmPartialImportState struct {
+	RealmPartialImportArgs
+	Added       int `pulumi:"added"`
+	Skipped     int `pulumi:"skipped"`
+	Overwritten int `pulumi:"overwritten"`
+}
+
+// partialImportResult is the admin API response of a partial import
+type partialImportResult struct {
+	Added       int                     `json:"added"`
+	Skipped     int                     `json:"skipped"`
+	Overwritten int                     `json:"overwritten"`
+	Results     []partialImportResource `json:"results"`
+}
+
+// partialImportResource reports what a partial import did with one object: ADDED, SKIPPED or OVERWRITTEN
+type partialImportResource struct {
+	Action       string `json:"action"`
+	ResourceType string `json:"resourceType"`
+	ResourceName string `json:"resourceName"`
+}
+
+// Annotate provides schema documentation for the RealmPartialImport resource
+func (r *RealmPartialImport) Annotate(a infer.Annotator) {
+	a.Describe(&r, "Imports users, clients, groups, roles and identity providers into an existing realm using the partial import API")
+}
+
+func (args *RealmPartialImportArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.Content, "JSON document in the format of a realm export, e.g. {\"users\": [...], \"clients\": [...], \"groups\": [...], \"roles\": {\"realm\": [...]}}")
+	a.Describe(&args.IfResourceExists, "What to do when an imported object already exists: SKIP, OVERWRITE or FAIL")
+
+	a.SetDefault(&args.IfResourceExists, "SKIP")
+}
+
+func (state *RealmPartialImportState) Annotate(a infer.Annotator) {
+	a.Describe(&state.Added, "Number of objects added by the last import")
+	a.Describe(&state.Skipped, "Number of objects skipped by the last import")
+	a.Describe(&state.Overwritten, "Number of objects overwritten by the last import")
+}
+
+func (r *RealmPartialImport) Create(ctx context.Context, req infer.CreateRequest[RealmPartialImportArgs]) (infer.CreateResponse[RealmPartialImportState], error) {
+	if req.DryRun {
+		return infer.CreateResponse[RealmPartialImportState]{
+			ID:     req.Inputs.RealmID,
+			Output: RealmPartialImportState{RealmPartialImportArgs: req.Inputs},
+		}, nil
+	}
+
+	state, err := applyRealmPartialImport(ctx, req.Inputs)
+	if err != nil {
+		return infer.CreateResponse[RealmPartialImportState]{}, err
+	}
+
+	return infer.CreateResponse[RealmPartialImportState]{
+		ID:     req.Inputs.RealmID,
+		Output: state,
+	}, nil
+}
+
+func (r *RealmPartialImport) Update(ctx context.Context, req infer.UpdateRequest[RealmPartialImportArgs, RealmPartialImportState]) (infer.UpdateResponse[RealmPartialImportState], error) {
+	if req.DryRun {
+		return infer.UpdateResponse[RealmPartialImportState]{
+			Output: RealmPartialImportState{RealmPartialImportArgs: req.Inputs},
+		}, nil
+	}
+
+	state, err := applyRealmPartialImport(ctx, req.Inputs)
+	if err != nil {
+		return infer.UpdateResponse[RealmPartialImportState]{}, err
+	}
+
+	return infer.UpdateResponse[RealmPartialImportState]{
+		Output: state,
+	}, nil
+}
+
+// Delete leaves the imported objects in place, since they are not tracked individually
+func (r *RealmPartialImport) Delete(ctx context.Context, req infer.DeleteRequest[RealmPartialImportState]) (infer.DeleteResponse, error) {
+	return infer.DeleteResponse{}, nil
+}
+
+func applyRealmPartialImport(ctx context.Context, args RealmPartialImportArgs) (RealmPartialImportState, error) {
+	switch args.IfResourceExists {
+	case "SKIP", "OVERWRITE", "FAIL":
+	default:
+		return RealmPartialImportState{}, fmt.Errorf("invalid ifResourceExists %q: must be SKIP, OVERWRITE or FAIL", args.IfResourceExists)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(args.Content), &body); err != nil {
+		return RealmPartialImportState{}, fmt.Errorf("failed to parse partial import content: %w", err)
+	}
+	body["ifResourceExists"] = args.IfResourceExists
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return RealmPartialImportState{}, err
+	}
+
+	var result partialImportResult
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetBody(body).
+		SetResult(&result).
+		Post(adminRealmURL(ctx, args.RealmID, "partialImport"))
+	if err := checkResponse(resp, err); err != nil {
+		return RealmPartialImportState{}, fmt.Errorf("failed to import into realm %s: %w", args.RealmID, err)
+	}
+
+	return RealmPartialImportState{
+		RealmPartialImportArgs: args,
+		Added:                  result.Added,
+		Skipped:                result.Skipped,
+		Overwritten:            result.Overwritten,
+	}, nil
+}
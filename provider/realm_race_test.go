@@ -0,0 +1,265 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blang/semver"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/integration"
+	"github.com/pulumi/pulumi/sdk/v3/go/property"
+)
+
+// keycloakServer serves the parts of the admin API realms use, over HTTP, so that requests go through the shared
+// gocloak client with its token cache, realm cache and request limiter. Tokens expire after a second, and a PUT
+// only changes the fields it sends, as in Keycloak
+type keycloakServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	realms    map[string]map[string]any
+	tokens    map[string]bool
+	refreshed int
+	inFlight  int
+	peak      int
+}
+
+// issuedTokens numbers the tokens of all servers, which like Keycloak's must not repeat: the provider tells the
+// tokens it renewed apart by their value alone
+var issuedTokens atomic.Int64
+
+func newKeycloakServer(t *testing.T) *keycloakServer {
+	server := &keycloakServer{realms: map[string]map[string]any{}, tokens: map[string]bool{}}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.serve))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (s *keycloakServer) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.inFlight++
+	s.peak = max(s.peak, s.inFlight)
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+	}()
+	// Keeps requests in flight long enough to overlap
+	time.Sleep(time.Millisecond)
+
+	if strings.HasSuffix(r.URL.Path, "/protocol/openid-connect/token") {
+		s.issueToken(w, r.FormValue("grant_type") == "refresh_token")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.tokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")] {
+		http.Error(w, `{"error":"HTTP 401 Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/admin/"), "/")
+	name, _ := strings.CutPrefix(path, "realms/")
+	switch {
+	case path == "serverinfo":
+		writeJSON(w, http.StatusOK, map[string]any{"systemInfo": map[string]any{"version": "26.0.0"}})
+	case path == "realms" && r.Method == http.MethodPost:
+		var realm map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&realm); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		name, _ := realm["realm"].(string)
+		if _, ok := s.realms[name]; ok {
+			writeJSON(w, http.StatusConflict, map[string]any{"errorMessage": "Conflict detected."})
+			return
+		}
+		realm["id"] = name
+		s.realms[name] = realm
+		w.Header().Set("Location", s.URL+"/admin/realms/"+name)
+		w.WriteHeader(http.StatusCreated)
+	case s.realms[name] == nil:
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "Realm not found."})
+	case r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.realms[name])
+	case r.Method == http.MethodPut:
+		var update map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		maps.Copy(s.realms[name], update)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete:
+		delete(s.realms, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "unexpected request", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *keycloakServer) issueToken(w http.ResponseWriter, refresh bool) {
+	token := fmt.Sprintf("token-%d", issuedTokens.Add(1))
+	s.mu.Lock()
+	if refresh {
+		s.refreshed++
+	}
+	s.tokens[token] = true
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token":       token,
+		"expires_in":         1,
+		"refresh_token":      "refresh-" + token,
+		"refresh_expires_in": 60,
+		"token_type":         "Bearer",
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func (s *keycloakServer) realm(name string) map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return maps.Clone(s.realms[name])
+}
+
+func (s *keycloakServer) stats() (realms, peak, refreshed int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.realms), s.peak, s.refreshed
+}
+
+// TestRealmConcurrentOperations runs the operations of many realms at once, as pulumi up does, so that go test
+// -race covers the state they share. It runs long enough for the admin token to be refreshed meanwhile. Updates of a
+// realm and of its WebAuthn policy both rewrite the realm, and neither may be lost
+func TestRealmConcurrentOperations(t *testing.T) {
+	keycloak := newKeycloakServer(t)
+	server, err := integration.NewServer(context.Background(), "keycloak", semver.MustParse("1.0.0"),
+		integration.WithProvider(Provider()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const maxConcurrentRequests = 4
+	if err := server.Configure(p.ConfigureRequest{Args: property.NewMap(map[string]property.Value{
+		"url":                   property.New(keycloak.URL),
+		"username":              property.New("admin"),
+		"password":              property.New("admin"),
+		"tokenRefreshMargin":    property.New(0.0),
+		"maxConcurrentRequests": property.New(float64(maxConcurrentRequests)),
+	})}); err != nil {
+		t.Fatal(err)
+	}
+	// The background refresh would otherwise keep calling the closed server
+	t.Cleanup(func() {
+		entry := tokenCacheEntryFor(ProviderConfig{URL: keycloak.URL, Username: "admin", Password: "admin"})
+		entry.mu.Lock()
+		defer entry.mu.Unlock()
+		if entry.refresh != nil {
+			entry.refresh.Stop()
+		}
+	})
+
+	deadline := time.Now().Add(1500 * time.Millisecond)
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for round := 0; time.Now().Before(deadline); round++ {
+				name := fmt.Sprintf("realm-%d-%d", i, round)
+				if err := exerciseRealm(server, keycloak, name); err != nil {
+					t.Errorf("%s: %v", name, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	realms, peak, refreshed := keycloak.stats()
+	if realms != 0 {
+		t.Errorf("%d realms left after delete", realms)
+	}
+	if peak > maxConcurrentRequests {
+		t.Errorf("want at most %d requests in flight, got %d", maxConcurrentRequests, peak)
+	}
+	if refreshed == 0 {
+		t.Error("the admin token was never refreshed")
+	}
+}
+
+// exerciseRealm creates a realm, then updates and reads it while creating its WebAuthn policy, and deletes it
+func exerciseRealm(server integration.Server, keycloak *keycloakServer, name string) error {
+	created, err := server.Create(p.CreateRequest{Urn: testURN("Realm", name), Properties: realmInputs(name, nil)})
+	if err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		inputs := realmInputs(name, map[string]property.Value{"displayName": property.New("Realm " + name)})
+		_, err := server.Update(p.UpdateRequest{ID: created.ID, Urn: testURN("Realm", name), State: created.Properties, Inputs: inputs})
+		if err != nil {
+			errs <- fmt.Errorf("update: %w", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		inputs := property.NewMap(map[string]property.Value{
+			"realmId":                property.New(name),
+			"relyingPartyEntityName": property.New("RP " + name),
+		})
+		if _, err := server.Create(p.CreateRequest{Urn: testURN("RealmWebAuthnPasswordlessPolicy", name), Properties: inputs}); err != nil {
+			errs <- fmt.Errorf("create WebAuthn policy: %w", err)
+		}
+	}()
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := server.Read(p.ReadRequest{ID: created.ID, Urn: testURN("Realm", name), Properties: created.Properties})
+			if err != nil {
+				errs <- fmt.Errorf("read: %w", err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		return err
+	}
+
+	read, err := server.Read(p.ReadRequest{ID: created.ID, Urn: testURN("Realm", name), Properties: created.Properties})
+	if err != nil {
+		return fmt.Errorf("read: %w", err)
+	}
+	if got := read.Properties.Get("displayName"); !got.IsString() || got.AsString() != "Realm "+name {
+		return fmt.Errorf("update of displayName lost, read %v", got)
+	}
+	if got := keycloak.realm(name)["webAuthnPolicyPasswordlessRpEntityName"]; got != "RP "+name {
+		return fmt.Errorf("update of the WebAuthn policy lost, found %v", got)
+	}
+	if err := server.Delete(p.DeleteRequest{ID: created.ID, Urn: testURN("Realm", name), Properties: read.Properties}); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"testing"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/property"
+)
+
+func TestRealmLifecycleWithFakeClient(t *testing.T) {
+	fake := newFakeKeycloak()
+	server := newFakeServer(t, fake)
+
+	inputs := realmInputs("acme", map[string]property.Value{"displayName": property.New("Acme")})
+	created := createRealm(t, server, inputs)
+	ensureEqual(t, "id", "acme", created.ID)
+	ensureEqual(t, "displayName", "Acme", stringProperty(t, created.Properties, "displayName"))
+	ensureEqual(t, "CreateRealm calls", 1, fake.callCount("CreateRealm"))
+
+	updatedInputs := realmInputs("acme", map[string]property.Value{"displayName": property.New("Acme Corp")})
+	updated, err := server.Update(p.UpdateRequest{
+		ID: created.ID, Urn: testURN("Realm", "acme"), State: created.Properties, Inputs: updatedInputs,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ensureEqual(t, "updated displayName", "Acme Corp", stringProperty(t, updated.Properties, "displayName"))
+
+	read, err := server.Read(p.ReadRequest{
+		ID: created.ID, Urn: testURN("Realm", "acme"), Properties: updated.Properties, Inputs: updatedInputs,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ensureEqual(t, "read displayName", "Acme Corp", stringProperty(t, read.Properties, "displayName"))
+
+	if err := server.Delete(p.DeleteRequest{ID: created.ID, Urn: testURN("Realm", "acme"), Properties: read.Properties}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fake.GetRealm(t.Context(), "", "acme"); !isNotFound(err) {
+		t.Errorf("realm still exists after delete: %v", err)
+	}
+}
+
+func TestRealmCreateReportsExistingRealm(t *testing.T) {
+	fake := newFakeKeycloak()
+	server := newFakeServer(t, fake)
+	createRealm(t, server, realmInputs("acme", nil))
+
+	_, err := server.Create(p.CreateRequest{Urn: testURN("Realm", "acme-2"), Properties: realmInputs("acme", nil)})
+	if err == nil {
+		t.Fatal("creating a realm that already exists succeeded")
+	}
+}
+
+func TestRealmCreateChecksAdminRealm(t *testing.T) {
+	fake := newFakeKeycloak()
+	server := newFakeServer(t, fake)
+	createRealm(t, server, realmInputs("acme", nil))
+
+	logins := fake.callCount("login")
+	if _, err := server.Create(p.CreateRequest{Urn: testURN("Realm", "beta"), Properties: realmInputs("beta", nil), DryRun: true}); err != nil {
+		t.Fatal(err)
+	}
+	ensureEqual(t, "logins to preview a new realm", 0, fake.callCount("login")-logins)
+
+	configure(t, server, map[string]property.Value{"realm": property.New("acme")})
+	if _, err := server.Create(p.CreateRequest{Urn: testURN("Realm", "beta"), Properties: realmInputs("beta", nil), DryRun: true}); err == nil {
+		t.Error("previewing a new realm as an admin of realm acme succeeded")
+	}
+
+	adopt := map[string]property.Value{"adoptExisting": property.New(true), "displayName": property.New("Acme")}
+	preview, err := server.Create(p.CreateRequest{Urn: testURN("Realm", "acme"), Properties: realmInputs("acme", adopt), DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ensureEqual(t, "previewed displayName", "Acme", stringProperty(t, preview.Properties, "displayName"))
+
+	if _, err := server.Create(p.CreateRequest{Urn: testURN("Realm", "beta"), Properties: realmInputs("beta", adopt)}); err == nil {
+		t.Error("adopting a missing realm as an admin of realm acme created it")
+	}
+	adopted, err := server.Create(p.CreateRequest{Urn: testURN("Realm", "acme"), Properties: realmInputs("acme", adopt)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ensureEqual(t, "adopted displayName", "Acme", stringProperty(t, adopted.Properties, "displayName"))
+	ensureEqual(t, "CreateRealm calls", 1, fake.callCount("CreateRealm"))
+}
+
+func TestRealmUpdateKeepsMaskedSmtpPassword(t *testing.T) {
+	fake := newFakeKeycloak()
+	server := newFakeServer(t, fake)
+
+	inputs := realmInputs("acme", map[string]property.Value{"smtpServer": property.New(map[string]property.Value{
+		"host":     property.New("smtp.example.com"),
+		"from":     property.New("noreply@example.com"),
+		"auth":     property.New(true),
+		"username": property.New("mailer"),
+		"password": property.New("secret"),
+	})})
+	created := createRealm(t, server, inputs)
+
+	if _, err := server.Update(p.UpdateRequest{ID: created.ID, Urn: testURN("Realm", "acme"), State: created.Properties, Inputs: inputs}); err != nil {
+		t.Fatal(err)
+	}
+	ensureEqual(t, "UpdateRealm calls for unchanged inputs", 0, fake.callCount("UpdateRealm"))
+}
+
+func TestRealmMigratesFlatSmtpServerState(t *testing.T) {
+	fake := newFakeKeycloak()
+	server := newFakeServer(t, fake)
+
+	smtpServer := property.New(map[string]property.Value{
+		"host":     property.New("smtp.example.com"),
+		"port":     property.New(587.0),
+		"from":     property.New("noreply@example.com"),
+		"startTls": property.New(true),
+	})
+	inputs := realmInputs("acme", map[string]property.Value{"smtpServer": smtpServer})
+	created := createRealm(t, server, inputs)
+
+	// The state as recorded with Keycloak's flat SMTP format
+	oldState := created.Properties.Set("smtpServer", property.New(map[string]property.Value{
+		"host":     property.New("smtp.example.com"),
+		"port":     property.New("587"),
+		"from":     property.New("noreply@example.com"),
+		"starttls": property.New("true"),
+		"auth":     property.New("false"),
+	}))
+
+	diff, err := server.Diff(p.DiffRequest{ID: created.ID, Urn: testURN("Realm", "acme"), State: oldState, Inputs: inputs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.HasChanges {
+		t.Errorf("unchanged inputs diff against a migrated state: %v", diff.DetailedDiff)
+	}
+
+	updatedInputs := inputs.Set("displayName", property.New("Acme"))
+	updated, err := server.Update(p.UpdateRequest{
+		ID: created.ID, Urn: testURN("Realm", "acme"), State: oldState, Inputs: updatedInputs,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	smtp := updated.Properties.Get("smtpServer").AsMap()
+	ensureEqual(t, "port", 587.0, smtp.Get("port").AsNumber())
+	ensureEqual(t, "startTls", true, smtp.Get("startTls").AsBool())
+	ensureEqual(t, "displayName", "Acme", stringProperty(t, updated.Properties, "displayName"))
+}
+
+func TestRealmKeepsCurrentSmtpServerState(t *testing.T) {
+	state := realmStateFlatSmtp{SmtpServer: map[string]string{"host": "smtp.example.com", "fromName": "Acme"}}
+	result, err := migrateFlatSmtpServer(t.Context(), state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Result != nil {
+		t.Errorf("a state in the current format was migrated: %+v", result.Result.SmtpServer)
+	}
+}
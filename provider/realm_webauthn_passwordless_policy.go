@@ -0,0 +1,250 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// RealmWebAuthnPasswordlessPolicy manages the passwordless WebAuthn policy of a realm.
+// This is a separate set of realm settings from the two-factor WebAuthn policy and is
+// used by the "WebAuthn Passwordless" authenticator.
+type RealmWebAuthnPasswordlessPolicy struct{}
+
+type RealmWebAuthnPasswordlessPolicyArgs struct {
+	RealmID                         string   `pulumi:"realmId" provider:"replaceOnChanges"`
+	RelyingPartyEntityName          *string  `pulumi:"relyingPartyEntityName,optional"`
+	RelyingPartyID                  *string  `pulumi:"relyingPartyId,optional"`
+	SignatureAlgorithms             []string `pulumi:"signatureAlgorithms,optional"`
+	AttestationConveyancePreference *string  `pulumi:"attestationConveyancePreference,optional"`
+	AuthenticatorAttachment         *string  `pulumi:"authenticatorAttachment,optional"`
+	RequireResidentKey              *string  `pulumi:"requireResidentKey,optional"`
+	UserVerificationRequirement     *string  `pulumi:"userVerificationRequirement,optional"`
+	CreateTimeout                   *int     `pulumi:"createTimeout,optional"`
+	AvoidSameAuthenticatorRegister  *bool    `pulumi:"avoidSameAuthenticatorRegister,optional"`
+	AcceptableAaguids               []string `pulumi:"acceptableAaguids,optional"`
+}
+
+type RealmWebAuthnPasswordlessPolicyState struct {
+	RealmWebAuthnPasswordlessPolicyArgs
+}
+
+// Annotate provides schema documentation for the RealmWebAuthnPasswordlessPolicy resource
+func (r *RealmWebAuthnPasswordlessPolicy) Annotate(a infer.Annotator) {
+	a.Describe(&r, "The passwordless WebAuthn policy of a Keycloak realm, managed separately from the two-factor WebAuthn policy")
+}
+
+func (args *RealmWebAuthnPasswordlessPolicyArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm the policy belongs to")
+	a.Describe(&args.RelyingPartyEntityName, "Human-readable relying party name displayed by authenticators")
+	a.Describe(&args.RelyingPartyID, "Relying party ID, usually the effective domain of the realm")
+	a.Describe(&args.SignatureAlgorithms, "Acceptable signature algorithms (e.g., ES256, RS256)")
+	a.Describe(&args.AttestationConveyancePreference, "Attestation conveyance preference: not specified, none, indirect or direct")
+	a.Describe(&args.AuthenticatorAttachment, "Authenticator attachment: not specified, platform or cross-platform")
+	a.Describe(&args.RequireResidentKey, "Whether a resident key (discoverable credential) is required: not specified, Yes or No")
+	a.Describe(&args.UserVerificationRequirement, "User verification requirement: not specified, required, preferred or discouraged")
+	a.Describe(&args.CreateTimeout, "Timeout in seconds for registering a passwordless credential")
+	a.Describe(&args.AvoidSameAuthenticatorRegister, "Whether registering the same authenticator twice is rejected")
+	a.Describe(&args.AcceptableAaguids, "AAGUIDs of authenticators that are allowed to register")
+}
+
+func (args RealmWebAuthnPasswordlessPolicyArgs) applyTo(realm *gocloak.RealmRepresentation) {
+	if args.RelyingPartyEntityName != nil {
+		realm.WebAuthnPolicyPasswordlessRpEntityName = args.RelyingPartyEntityName
+	}
+	if args.RelyingPartyID != nil {
+		realm.WebAuthnPolicyPasswordlessRpID = args.RelyingPartyID
+	}
+	if args.SignatureAlgorithms != nil {
+		realm.WebAuthnPolicyPasswordlessSignatureAlgorithms = &args.SignatureAlgorithms
+	}
+	if args.AttestationConveyancePreference != nil {
+		realm.WebAuthnPolicyPasswordlessAttestationConveyancePreference = args.AttestationConveyancePreference
+	}
+	if args.AuthenticatorAttachment != nil {
+		realm.WebAuthnPolicyPasswordlessAuthenticatorAttachment = args.AuthenticatorAttachment
+	}
+	if args.RequireResidentKey != nil {
+		realm.WebAuthnPolicyPasswordlessRequireResidentKey = args.RequireResidentKey
+	}
+	if args.UserVerificationRequirement != nil {
+		realm.WebAuthnPolicyPasswordlessUserVerificationRequirement = args.UserVerificationRequirement
+	}
+	if args.CreateTimeout != nil {
+		realm.WebAuthnPolicyPasswordlessCreateTimeout = args.CreateTimeout
+	}
+	if args.AvoidSameAuthenticatorRegister != nil {
+		realm.WebAuthnPolicyPasswordlessAvoidSameAuthenticatorRegister = args.AvoidSameAuthenticatorRegister
+	}
+	if args.AcceptableAaguids != nil {
+		realm.WebAuthnPolicyPasswordlessAcceptableAaguids = &args.AcceptableAaguids
+	}
+}
+
+func (r *RealmWebAuthnPasswordlessPolicy) Create(ctx context.Context, req infer.CreateRequest[RealmWebAuthnPasswordlessPolicyArgs]) (infer.CreateResponse[RealmWebAuthnPasswordlessPolicyState], error) {
+	if req.DryRun {
+		return infer.CreateResponse[RealmWebAuthnPasswordlessPolicyState]{
+			ID:     req.Inputs.RealmID,
+			Output: RealmWebAuthnPasswordlessPolicyState{req.Inputs},
+		}, nil
+	}
+
+	state, err := applyWebAuthnPasswordlessPolicy(ctx, req.Inputs)
+	if err != nil {
+		return infer.CreateResponse[RealmWebAuthnPasswordlessPolicyState]{}, err
+	}
+
+	return infer.CreateResponse[RealmWebAuthnPasswordlessPolicyState]{
+		ID:     req.Inputs.RealmID,
+		Output: state,
+	}, nil
+}
+
+func (r *RealmWebAuthnPasswordlessPolicy) Update(ctx context.Context, req infer.UpdateRequest[RealmWebAuthnPasswordlessPolicyArgs, RealmWebAuthnPasswordlessPolicyState]) (infer.UpdateResponse[RealmWebAuthnPasswordlessPolicyState], error) {
+	if req.DryRun {
+		return infer.UpdateResponse[RealmWebAuthnPasswordlessPolicyState]{
+			Output: RealmWebAuthnPasswordlessPolicyState{req.Inputs},
+		}, nil
+	}
+
+	state, err := applyWebAuthnPasswordlessPolicy(ctx, req.Inputs)
+	if err != nil {
+		return infer.UpdateResponse[RealmWebAuthnPasswordlessPolicyState]{}, err
+	}
+
+	return infer.UpdateResponse[RealmWebAuthnPasswordlessPolicyState]{
+		Output: state,
+	}, nil
+}
+
+// Delete leaves the policy settings in place, since they are part of the realm itself
+func (r *RealmWebAuthnPasswordlessPolicy) Delete(ctx context.Context, req infer.DeleteRequest[RealmWebAuthnPasswordlessPolicyState]) (infer.DeleteResponse, error) {
+	return infer.DeleteResponse{}, nil
+}
+
+func (r *RealmWebAuthnPasswordlessPolicy) Read(ctx context.Context, req infer.ReadRequest[RealmWebAuthnPasswordlessPolicyArgs, RealmWebAuthnPasswordlessPolicyState]) (infer.ReadResponse[RealmWebAuthnPasswordlessPolicyArgs, RealmWebAuthnPasswordlessPolicyState], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.ReadResponse[RealmWebAuthnPasswordlessPolicyArgs, RealmWebAuthnPasswordlessPolicyState]{}, err
+	}
+
+	realmName := req.ID
+	if realmName == "" {
+		realmName = req.State.RealmID
+	}
+
+	realm, err := client.GetRealm(ctx, token, realmName)
+	if isNotFound(err) {
+		return infer.ReadResponse[RealmWebAuthnPasswordlessPolicyArgs, RealmWebAuthnPasswordlessPolicyState]{}, nil
+	}
+	if err != nil {
+		return infer.ReadResponse[RealmWebAuthnPasswordlessPolicyArgs, RealmWebAuthnPasswordlessPolicyState]{}, fmt.Errorf("failed to get realm: %w", err)
+	}
+
+	state := webAuthnPasswordlessPolicyFromRealm(realmName, realm)
+
+	// An import has no inputs yet and takes over the whole policy. Otherwise fields without an input stay unmanaged
+	if req.Inputs.RealmID != "" {
+		state.RealmWebAuthnPasswordlessPolicyArgs = state.managedBy(req.Inputs)
+	}
+
+	return infer.ReadResponse[RealmWebAuthnPasswordlessPolicyArgs, RealmWebAuthnPasswordlessPolicyState]{
+		ID:     realmName,
+		Inputs: state.RealmWebAuthnPasswordlessPolicyArgs,
+		State:  state,
+	}, nil
+}
+
+// managedBy keeps the live policy fields that the given inputs set
+func (args RealmWebAuthnPasswordlessPolicyArgs) managedBy(inputs RealmWebAuthnPasswordlessPolicyArgs) RealmWebAuthnPasswordlessPolicyArgs {
+	managed := RealmWebAuthnPasswordlessPolicyArgs{RealmID: args.RealmID}
+	if inputs.RelyingPartyEntityName != nil {
+		managed.RelyingPartyEntityName = args.RelyingPartyEntityName
+	}
+	if inputs.RelyingPartyID != nil {
+		managed.RelyingPartyID = args.RelyingPartyID
+	}
+	if inputs.SignatureAlgorithms != nil {
+		managed.SignatureAlgorithms = args.SignatureAlgorithms
+	}
+	if inputs.AttestationConveyancePreference != nil {
+		managed.AttestationConveyancePreference = args.AttestationConveyancePreference
+	}
+	if inputs.AuthenticatorAttachment != nil {
+		managed.AuthenticatorAttachment = args.AuthenticatorAttachment
+	}
+	if inputs.RequireResidentKey != nil {
+		managed.RequireResidentKey = args.RequireResidentKey
+	}
+	if inputs.UserVerificationRequirement != nil {
+		managed.UserVerificationRequirement = args.UserVerificationRequirement
+	}
+	if inputs.CreateTimeout != nil {
+		managed.CreateTimeout = args.CreateTimeout
+	}
+	if inputs.AvoidSameAuthenticatorRegister != nil {
+		managed.AvoidSameAuthenticatorRegister = args.AvoidSameAuthenticatorRegister
+	}
+	if inputs.AcceptableAaguids != nil {
+		managed.AcceptableAaguids = args.AcceptableAaguids
+	}
+	return managed
+}
+
+func applyWebAuthnPasswordlessPolicy(ctx context.Context, args RealmWebAuthnPasswordlessPolicyArgs) (RealmWebAuthnPasswordlessPolicyState, error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return RealmWebAuthnPasswordlessPolicyState{}, err
+	}
+
+	defer lockRealm(args.RealmID)()
+
+	realm, err := client.GetRealm(ctx, token, args.RealmID)
+	if err != nil {
+		return RealmWebAuthnPasswordlessPolicyState{}, fmt.Errorf("failed to get realm: %w", err)
+	}
+
+	args.applyTo(realm)
+
+	err = client.UpdateRealm(ctx, token, *realm)
+	if err != nil {
+		return RealmWebAuthnPasswordlessPolicyState{}, fmt.Errorf("failed to update passwordless WebAuthn policy: %w", err)
+	}
+
+	realm, err = client.GetRealm(ctx, token, args.RealmID)
+	if err != nil {
+		return RealmWebAuthnPasswordlessPolicyState{}, fmt.Errorf("failed to get realm: %w", err)
+	}
+
+	// Keycloak fills in every field, and the defaults of those without an input would otherwise show up as changes
+	state := webAuthnPasswordlessPolicyFromRealm(args.RealmID, realm)
+	state.RealmWebAuthnPasswordlessPolicyArgs = state.managedBy(args)
+	return state, nil
+}
+
+func webAuthnPasswordlessPolicyFromRealm(realmName string, realm *gocloak.RealmRepresentation) RealmWebAuthnPasswordlessPolicyState {
+	state := RealmWebAuthnPasswordlessPolicyState{
+		RealmWebAuthnPasswordlessPolicyArgs{
+			RealmID:                         realmName,
+			RelyingPartyEntityName:          realm.WebAuthnPolicyPasswordlessRpEntityName,
+			RelyingPartyID:                  realm.WebAuthnPolicyPasswordlessRpID,
+			AttestationConveyancePreference: realm.WebAuthnPolicyPasswordlessAttestationConveyancePreference,
+			AuthenticatorAttachment:         realm.WebAuthnPolicyPasswordlessAuthenticatorAttachment,
+			RequireResidentKey:              realm.WebAuthnPolicyPasswordlessRequireResidentKey,
+			UserVerificationRequirement:     realm.WebAuthnPolicyPasswordlessUserVerificationRequirement,
+			CreateTimeout:                   realm.WebAuthnPolicyPasswordlessCreateTimeout,
+			AvoidSameAuthenticatorRegister:  realm.WebAuthnPolicyPasswordlessAvoidSameAuthenticatorRegister,
+		},
+	}
+
+	if realm.WebAuthnPolicyPasswordlessSignatureAlgorithms != nil {
+		state.SignatureAlgorithms = *realm.WebAuthnPolicyPasswordlessSignatureAlgorithms
+	}
+	if realm.WebAuthnPolicyPasswordlessAcceptableAaguids != nil {
+		state.AcceptableAaguids = *realm.WebAuthnPolicyPasswordlessAcceptableAaguids
+	}
+
+	return state
+}
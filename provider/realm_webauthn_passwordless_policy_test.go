@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"testing"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/property"
+)
+
+func TestWebAuthnPasswordlessPolicyKeepsUnsetFieldsUnmanaged(t *testing.T) {
+	fake := newFakeKeycloak()
+	server := newFakeServer(t, fake)
+	createRealm(t, server, realmInputs("acme", nil))
+
+	// Keycloak fills in defaults for the fields a program leaves unset
+	realm, _ := fake.GetRealm(t.Context(), "", "acme")
+	realm.WebAuthnPolicyPasswordlessSignatureAlgorithms = &[]string{"ES256", "RS256"}
+	if err := fake.UpdateRealm(t.Context(), "", *realm); err != nil {
+		t.Fatal(err)
+	}
+
+	urn := testURN("RealmWebAuthnPasswordlessPolicy", "acme")
+	inputs := property.NewMap(map[string]property.Value{
+		"realmId":                property.New("acme"),
+		"relyingPartyEntityName": property.New("Acme"),
+	})
+	created, err := server.Create(p.CreateRequest{Urn: urn, Properties: inputs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := created.Properties.GetOk("signatureAlgorithms"); ok {
+		t.Errorf("create recorded a default the inputs leave unset: %v", created.Properties.Get("signatureAlgorithms"))
+	}
+	diff, err := server.Diff(p.DiffRequest{ID: created.ID, Urn: urn, State: created.Properties, Inputs: inputs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.HasChanges {
+		t.Errorf("unchanged inputs diff after create: %v", diff.DetailedDiff)
+	}
+
+	// A setting changed in the admin console, which the program leaves alone
+	realm, _ = fake.GetRealm(t.Context(), "", "acme")
+	realm.WebAuthnPolicyPasswordlessCreateTimeout = gocloak.IntP(120)
+	if err := fake.UpdateRealm(t.Context(), "", *realm); err != nil {
+		t.Fatal(err)
+	}
+
+	gets := fake.callCount("GetRealm")
+	read, err := server.Read(p.ReadRequest{ID: created.ID, Urn: urn, Properties: created.Properties, Inputs: inputs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ensureEqual(t, "GetRealm calls per read", 1, fake.callCount("GetRealm")-gets)
+	ensureEqual(t, "relyingPartyEntityName input", "Acme", stringProperty(t, read.Inputs, "relyingPartyEntityName"))
+	for _, values := range []property.Map{read.Inputs, read.Properties} {
+		if _, ok := values.GetOk("createTimeout"); ok {
+			t.Errorf("refresh made createTimeout managed: %v", values.Get("createTimeout"))
+		}
+	}
+	diff, err = server.Diff(p.DiffRequest{ID: created.ID, Urn: urn, State: read.Properties, Inputs: inputs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.HasChanges {
+		t.Errorf("unchanged inputs diff after refresh: %v", diff.DetailedDiff)
+	}
+
+	imported, err := server.Read(p.ReadRequest{ID: "acme", Urn: urn})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ensureEqual(t, "imported createTimeout input", 120.0, imported.Inputs.Get("createTimeout").AsNumber())
+}
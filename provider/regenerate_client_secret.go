@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// RegenerateClientSecret rotates the secret of a confidential client
+type RegenerateClientSecret struct{}
+
+type RegenerateClientSecretArgs struct {
+	RealmID  string `pulumi:"realmId"`
+	ClientID string `pulumi:"clientId"`
+}
+
+type RegenerateClientSecretResult struct {
+	Secret string `pulumi:"secret" provider:"secret"`
+}
+
+// Annotate provides schema documentation for the regenerateClientSecret function
+func (r *RegenerateClientSecret) Annotate(a infer.Annotator) {
+	a.Describe(&r, "Generates a new secret for a confidential client. The previous secret stops working immediately "+
+		"unless the realm has client secret rotation policies configured")
+}
+
+func (args *RegenerateClientSecretArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.ClientID, "The clientId of the client, as used in OAuth requests")
+}
+
+func (result *RegenerateClientSecretResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.Secret, "The new client secret")
+}
+
+func (r *RegenerateClientSecret) Invoke(ctx context.Context, req infer.FunctionRequest[RegenerateClientSecretArgs]) (infer.FunctionResponse[RegenerateClientSecretResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[RegenerateClientSecretResult]{}, err
+	}
+
+	idOfClient, err := clientUUID(ctx, client, token, req.Input.RealmID, req.Input.ClientID)
+	if err != nil {
+		return infer.FunctionResponse[RegenerateClientSecretResult]{}, err
+	}
+
+	credential, err := client.RegenerateClientSecret(ctx, token, req.Input.RealmID, idOfClient)
+	if err != nil {
+		return infer.FunctionResponse[RegenerateClientSecretResult]{}, fmt.Errorf("failed to regenerate secret of client %s: %w", req.Input.ClientID, err)
+	}
+
+	return infer.FunctionResponse[RegenerateClientSecretResult]{
+		Output: RegenerateClientSecretResult{Secret: gocloak.PString(credential.Value)},
+	}, nil
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// RemoveUserCredentials deletes credentials of a user, e.g. to reset MFA
+type RemoveUserCredentials struct{}
+
+type RemoveUserCredentialsArgs struct {
+	RealmID       string   `pulumi:"realmId"`
+	UserID        string   `pulumi:"userId"`
+	Types         []string `pulumi:"types,optional"`
+	CredentialIDs []string `pulumi:"credentialIds,optional"`
+}
+
+type RemoveUserCredentialsResult struct {
+	RemovedCredentialIDs []string `pulumi:"removedCredentialIds"`
+}
+
+// Annotate provides schema documentation for the removeUserCredentials function
+func (r *RemoveUserCredentials) Annotate(a infer.Annotator) {
+	a.Describe(&r, "Deletes credentials of a user, selected by type or ID. Passwords are only removed when selected explicitly")
+}
+
+func (args *RemoveUserCredentialsArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.UserID, "The ID of the user")
+	a.Describe(&args.Types, "Credential types to remove, e.g. otp, webauthn or webauthn-passwordless")
+	a.Describe(&args.CredentialIDs, "IDs of individual credentials to remove")
+}
+
+func (result *RemoveUserCredentialsResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.RemovedCredentialIDs, "IDs of the removed credentials")
+}
+
+func (r *RemoveUserCredentials) Invoke(ctx context.Context, req infer.FunctionRequest[RemoveUserCredentialsArgs]) (infer.FunctionResponse[RemoveUserCredentialsResult], error) {
+	if len(req.Input.Types) == 0 && len(req.Input.CredentialIDs) == 0 {
+		return infer.FunctionResponse[RemoveUserCredentialsResult]{}, fmt.Errorf("either types or credentialIds must be set")
+	}
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[RemoveUserCredentialsResult]{}, err
+	}
+
+	credentials, err := client.GetCredentials(ctx, token, req.Input.RealmID, req.Input.UserID)
+	if err != nil {
+		return infer.FunctionResponse[RemoveUserCredentialsResult]{}, fmt.Errorf("failed to get credentials of user %s: %w", req.Input.UserID, err)
+	}
+
+	result := RemoveUserCredentialsResult{RemovedCredentialIDs: []string{}}
+	for _, credential := range credentials {
+		credentialID := gocloak.PString(credential.ID)
+		if !slices.Contains(req.Input.Types, gocloak.PString(credential.Type)) && !slices.Contains(req.Input.CredentialIDs, credentialID) {
+			continue
+		}
+		if err := client.DeleteCredentials(ctx, token, req.Input.RealmID, req.Input.UserID, credentialID); err != nil && !isNotFound(err) {
+			return infer.FunctionResponse[RemoveUserCredentialsResult]{}, fmt.Errorf("failed to remove credential %s of user %s: %w", credentialID, req.Input.UserID, err)
+		}
+		result.RemovedCredentialIDs = append(result.RemovedCredentialIDs, credentialID)
+	}
+
+	return infer.FunctionResponse[RemoveUserCredentialsResult]{
+		Output: result,
+	}, nil
+}
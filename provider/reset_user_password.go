@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// ResetUserPassword sets the password of a user
+type ResetUserPassword struct{}
+
+type ResetUserPasswordArgs struct {
+	RealmID   string `pulumi:"realmId"`
+	UserID    string `pulumi:"userId"`
+	Password  string `pulumi:"password" provider:"secret"`
+	Temporary *bool  `pulumi:"temporary,optional"`
+}
+
+// Annotate provides schema documentation for the resetUserPassword function
+func (r *ResetUserPassword) Annotate(a infer.Annotator) {
+	a.Describe(&r, "Sets the password of a user. The password is subject to the realm's password policy")
+}
+
+func (args *ResetUserPasswordArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.UserID, "The ID of the user")
+	a.Describe(&args.Password, "The new password")
+	a.Describe(&args.Temporary, "Whether the user must change the password on next login")
+
+	a.SetDefault(&args.Temporary, false)
+}
+
+func (r *ResetUserPassword) Invoke(ctx context.Context, req infer.FunctionRequest[ResetUserPasswordArgs]) (infer.FunctionResponse[UserActionResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[UserActionResult]{}, err
+	}
+
+	err = client.SetPassword(ctx, token, req.Input.UserID, req.Input.RealmID, req.Input.Password, gocloak.PBool(req.Input.Temporary))
+	if err != nil {
+		return infer.FunctionResponse[UserActionResult]{}, fmt.Errorf("failed to reset password of user %s: %w", req.Input.UserID, err)
+	}
+
+	return infer.FunctionResponse[UserActionResult]{
+		Output: UserActionResult{UserID: req.Input.UserID},
+	}, nil
+}
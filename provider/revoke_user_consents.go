@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// RevokeUserConsents revokes the consent and offline tokens a user granted to a client
+type RevokeUserConsents struct{}
+
+type RevokeUserConsentsArgs struct {
+	RealmID  string `pulumi:"realmId"`
+	UserID   string `pulumi:"userId"`
+	ClientID string `pulumi:"clientId"`
+}
+
+// Annotate provides schema documentation for the revokeUserConsents function
+func (r *RevokeUserConsents) Annotate(a infer.Annotator) {
+	a.Describe(&r, "Revokes the consent and offline tokens a user granted to a client")
+}
+
+func (args *RevokeUserConsentsArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.UserID, "The ID of the user")
+	a.Describe(&args.ClientID, "The clientId of the client, as used in OAuth requests")
+}
+
+func (r *RevokeUserConsents) Invoke(ctx context.Context, req infer.FunctionRequest[RevokeUserConsentsArgs]) (infer.FunctionResponse[UserActionResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[UserActionResult]{}, err
+	}
+
+	err = client.RevokeUserConsents(ctx, token, req.Input.RealmID, req.Input.UserID, req.Input.ClientID)
+	if err != nil && !isNotFound(err) {
+		return infer.FunctionResponse[UserActionResult]{}, fmt.Errorf("failed to revoke consents of user %s for client %s: %w", req.Input.UserID, req.Input.ClientID, err)
+	}
+
+	return infer.FunctionResponse[UserActionResult]{
+		Output: UserActionResult{UserID: req.Input.UserID},
+	}, nil
+}
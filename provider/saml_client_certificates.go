@@ -0,0 +1,270 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// SamlClientCertificates manages the signing and encryption certificates of an existing SAML client.
+// Certificates can either be uploaded or generated by Keycloak, in which case the private key is
+// exported as a secret output for the service provider side.
+type SamlClientCertificates struct{}
+
+type SamlClientCertificatesArgs struct {
+	RealmID                       string  `pulumi:"realmId" provider:"replaceOnChanges"`
+	ClientID                      string  `pulumi:"clientId" provider:"replaceOnChanges"`
+	SigningCertificate            *string `pulumi:"signingCertificate,optional"`
+	GenerateSigningCertificate    *bool   `pulumi:"generateSigningCertificate,optional"`
+	EncryptionCertificate         *string `pulumi:"encryptionCertificate,optional"`
+	GenerateEncryptionCertificate *bool   `pulumi:"generateEncryptionCertificate,optional"`
+}
+
+type SamlClientCertificatesState struct {
+	SamlClientCertificatesArgs
+	CurrentSigningCertificate    *string `pulumi:"currentSigningCertificate,optional"`
+	CurrentEncryptionCertificate *string `pulumi:"currentEncryptionCertificate,optional"`
+	SigningPrivateKey            *string `pulumi:"signingPrivateKey,optional" provider:"secret"`
+	EncryptionPrivateKey         *string `pulumi:"encryptionPrivateKey,optional" provider:"secret"`
+}
+
+// Certificate attribute names used by Keycloak for SAML clients
+const (
+	samlSigningCertificateAttribute    = "saml.signing"
+	samlEncryptionCertificateAttribute = "saml.encryption"
+)
+
+// certificateRepresentation is the admin API wire format of a client certificate
+type certificateRepresentation struct {
+	PrivateKey  string `json:"privateKey,omitempty"`
+	PublicKey   string `json:"publicKey,omitempty"`
+	Certificate string `json:"certificate,omitempty"`
+	Kid         string `json:"kid,omitempty"`
+}
+
+// Annotate provides schema documentation for the SamlClientCertificates resource
+func (s *SamlClientCertificates) Annotate(a infer.Annotator) {
+	a.Describe(&s, "Signing and encryption certificates of an existing SAML client")
+}
+
+func (args *SamlClientCertificatesArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.ClientID, "The internal ID of the SAML client")
+	a.Describe(&args.SigningCertificate, "PEM encoded certificate used to verify requests signed by the client. Leave unset to keep or generate one")
+	a.Describe(&args.GenerateSigningCertificate, "Whether Keycloak should generate a signing key pair for the client")
+	a.Describe(&args.EncryptionCertificate, "PEM encoded certificate used to encrypt assertions sent to the client. Leave unset to keep or generate one")
+	a.Describe(&args.GenerateEncryptionCertificate, "Whether Keycloak should generate an encryption key pair for the client")
+}
+
+func (state *SamlClientCertificatesState) Annotate(a infer.Annotator) {
+	a.Describe(&state.CurrentSigningCertificate, "The signing certificate the client has, whether uploaded, generated or set outside of Pulumi")
+	a.Describe(&state.CurrentEncryptionCertificate, "The encryption certificate the client has, whether uploaded, generated or set outside of Pulumi")
+	a.Describe(&state.SigningPrivateKey, "Private key of a generated signing certificate, to be installed on the service provider")
+	a.Describe(&state.EncryptionPrivateKey, "Private key of a generated encryption certificate, to be installed on the service provider")
+}
+
+func (s *SamlClientCertificates) Create(ctx context.Context, req infer.CreateRequest[SamlClientCertificatesArgs]) (infer.CreateResponse[SamlClientCertificatesState], error) {
+	id := req.Inputs.RealmID + "/" + req.Inputs.ClientID
+
+	if req.DryRun {
+		return infer.CreateResponse[SamlClientCertificatesState]{
+			ID:     id,
+			Output: SamlClientCertificatesState{SamlClientCertificatesArgs: req.Inputs},
+		}, nil
+	}
+
+	state, err := applySamlClientCertificates(ctx, req.Inputs, SamlClientCertificatesState{})
+	if err != nil {
+		return infer.CreateResponse[SamlClientCertificatesState]{}, err
+	}
+
+	return infer.CreateResponse[SamlClientCertificatesState]{
+		ID:     id,
+		Output: state,
+	}, nil
+}
+
+func (s *SamlClientCertificates) Update(ctx context.Context, req infer.UpdateRequest[SamlClientCertificatesArgs, SamlClientCertificatesState]) (infer.UpdateResponse[SamlClientCertificatesState], error) {
+	if req.DryRun {
+		return infer.UpdateResponse[SamlClientCertificatesState]{
+			Output: SamlClientCertificatesState{
+				SamlClientCertificatesArgs:   req.Inputs,
+				CurrentSigningCertificate:    req.State.CurrentSigningCertificate,
+				CurrentEncryptionCertificate: req.State.CurrentEncryptionCertificate,
+				SigningPrivateKey:            req.State.SigningPrivateKey,
+				EncryptionPrivateKey:         req.State.EncryptionPrivateKey,
+			},
+		}, nil
+	}
+
+	state, err := applySamlClientCertificates(ctx, req.Inputs, req.State)
+	if err != nil {
+		return infer.UpdateResponse[SamlClientCertificatesState]{}, err
+	}
+
+	return infer.UpdateResponse[SamlClientCertificatesState]{
+		Output: state,
+	}, nil
+}
+
+// Delete leaves the certificates in place, since they are part of the client itself
+func (s *SamlClientCertificates) Delete(ctx context.Context, req infer.DeleteRequest[SamlClientCertificatesState]) (infer.DeleteResponse, error) {
+	return infer.DeleteResponse{}, nil
+}
+
+func (s *SamlClientCertificates) Read(ctx context.Context, req infer.ReadRequest[SamlClientCertificatesArgs, SamlClientCertificatesState]) (infer.ReadResponse[SamlClientCertificatesArgs, SamlClientCertificatesState], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.ReadResponse[SamlClientCertificatesArgs, SamlClientCertificatesState]{}, err
+	}
+
+	parts, err := parseResourceID(req.ID, "<realm>/<clientId>")
+	if err != nil {
+		return infer.ReadResponse[SamlClientCertificatesArgs, SamlClientCertificatesState]{}, err
+	}
+
+	state := req.State
+	state.RealmID, state.ClientID = parts[0], parts[1]
+	for attribute, fields := range map[string]struct{ input, current **string }{
+		samlSigningCertificateAttribute:    {&state.SigningCertificate, &state.CurrentSigningCertificate},
+		samlEncryptionCertificateAttribute: {&state.EncryptionCertificate, &state.CurrentEncryptionCertificate},
+	} {
+		certificate, err := getClientCertificate(ctx, client, token, state.RealmID, state.ClientID, attribute)
+		if err != nil {
+			if isNotFound(err) {
+				return infer.ReadResponse[SamlClientCertificatesArgs, SamlClientCertificatesState]{}, nil
+			}
+			return infer.ReadResponse[SamlClientCertificatesArgs, SamlClientCertificatesState]{}, err
+		}
+		*fields.current = nil
+		if certificate.Certificate != "" {
+			*fields.current = &certificate.Certificate
+		}
+		// An uploaded certificate replaced outside of Pulumi shows up as a change, one left unset stays unmanaged
+		if *fields.input != nil && !sameCertificate(**fields.input, *fields.current) {
+			*fields.input = *fields.current
+		}
+	}
+
+	// An import has no inputs yet, and leaves the certificates unmanaged
+	inputs := req.Inputs
+	if inputs.RealmID == "" {
+		inputs = state.SamlClientCertificatesArgs
+	}
+
+	return infer.ReadResponse[SamlClientCertificatesArgs, SamlClientCertificatesState]{
+		ID:     req.ID,
+		Inputs: inputs,
+		State:  state,
+	}, nil
+}
+
+// applySamlClientCertificates uploads changed certificates and generates key pairs that were newly requested
+func applySamlClientCertificates(ctx context.Context, args SamlClientCertificatesArgs, previous SamlClientCertificatesState) (SamlClientCertificatesState, error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return SamlClientCertificatesState{}, err
+	}
+
+	// The live certificates go to outputs of their own, since the inputs may leave them unset
+	state := SamlClientCertificatesState{
+		SamlClientCertificatesArgs: args,
+		SigningPrivateKey:          previous.SigningPrivateKey,
+		EncryptionPrivateKey:       previous.EncryptionPrivateKey,
+	}
+
+	type certificateConfig struct {
+		attribute        string
+		certificate      *string
+		previous         *string
+		generate         *bool
+		previousGenerate *bool
+		stateCertificate **string
+		statePrivateKey  **string
+	}
+	configs := []certificateConfig{
+		{
+			attribute:        samlSigningCertificateAttribute,
+			certificate:      args.SigningCertificate,
+			previous:         previous.SigningCertificate,
+			generate:         args.GenerateSigningCertificate,
+			previousGenerate: previous.GenerateSigningCertificate,
+			stateCertificate: &state.CurrentSigningCertificate,
+			statePrivateKey:  &state.SigningPrivateKey,
+		},
+		{
+			attribute:        samlEncryptionCertificateAttribute,
+			certificate:      args.EncryptionCertificate,
+			previous:         previous.EncryptionCertificate,
+			generate:         args.GenerateEncryptionCertificate,
+			previousGenerate: previous.GenerateEncryptionCertificate,
+			stateCertificate: &state.CurrentEncryptionCertificate,
+			statePrivateKey:  &state.EncryptionPrivateKey,
+		},
+	}
+
+	for _, config := range configs {
+		switch {
+		case config.certificate != nil:
+			if ptrStringEqual(config.certificate, config.previous) {
+				continue
+			}
+			resp, err := client.GetRequestWithBearerAuth(ctx, token).
+				SetFormData(map[string]string{"keystoreFormat": "Certificate PEM"}).
+				SetFileReader("file", "certificate.pem", strings.NewReader(*config.certificate)).
+				Post(adminRealmURL(ctx, args.RealmID, "clients", args.ClientID, "certificates", config.attribute, "upload-certificate"))
+			if err := checkResponse(resp, err); err != nil {
+				return SamlClientCertificatesState{}, fmt.Errorf("failed to upload %s certificate: %w", config.attribute, err)
+			}
+			*config.statePrivateKey = nil
+		case gocloak.PBool(config.generate) && !gocloak.PBool(config.previousGenerate):
+			var generated certificateRepresentation
+			resp, err := client.GetRequestWithBearerAuth(ctx, token).
+				SetResult(&generated).
+				Post(adminRealmURL(ctx, args.RealmID, "clients", args.ClientID, "certificates", config.attribute, "generate"))
+			if err := checkResponse(resp, err); err != nil {
+				return SamlClientCertificatesState{}, fmt.Errorf("failed to generate %s certificate: %w", config.attribute, err)
+			}
+			*config.stateCertificate = &generated.Certificate
+			*config.statePrivateKey = &generated.PrivateKey
+			continue
+		}
+
+		certificate, err := getClientCertificate(ctx, client, token, args.RealmID, args.ClientID, config.attribute)
+		if err != nil {
+			return SamlClientCertificatesState{}, err
+		}
+		if certificate.Certificate != "" {
+			*config.stateCertificate = &certificate.Certificate
+		}
+	}
+
+	return state, nil
+}
+
+// sameCertificate compares an uploaded PEM certificate with the one Keycloak returns, which has no PEM armor
+func sameCertificate(uploaded string, current *string) bool {
+	if current == nil {
+		return false
+	}
+	normalize := func(certificate string) string {
+		certificate = strings.ReplaceAll(certificate, "-----BEGIN CERTIFICATE-----", "")
+		certificate = strings.ReplaceAll(certificate, "-----END CERTIFICATE-----", "")
+		return strings.Join(strings.Fields(certificate), "")
+	}
+	return normalize(uploaded) == normalize(*current)
+}
+
+func getClientCertificate(ctx context.Context, client KeycloakClient, token, realmName, idOfClient, attribute string) (certificateRepresentation, error) {
+	var certificate certificateRepresentation
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetResult(&certificate).
+		Get(adminRealmURL(ctx, realmName, "clients", idOfClient, "certificates", attribute))
+	if err := checkResponse(resp, err); err != nil {
+		return certificateRepresentation{}, fmt.Errorf("failed to get %s certificate: %w", attribute, err)
+	}
+	return certificate, nil
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/property"
+)
+
+// fakeClientCertificates serves the certificates of the SAML clients of one realm. Like Keycloak, it keeps uploaded
+// certificates without their PEM armor, and every client starts with a signing certificate
+type fakeClientCertificates struct {
+	mu           sync.Mutex
+	certificates map[string]string
+}
+
+func newFakeClientCertificates(mux *http.ServeMux) *fakeClientCertificates {
+	fake := &fakeClientCertificates{certificates: map[string]string{"saml-app/saml.signing": "MIIdefault"}}
+	mux.HandleFunc("GET /admin/realms/acme/clients/{client}/certificates/{attribute}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, certificateRepresentation{Certificate: fake.get(r.PathValue("client"), r.PathValue("attribute"))})
+	})
+	mux.HandleFunc("POST /admin/realms/acme/clients/{client}/certificates/{attribute}/generate", func(w http.ResponseWriter, r *http.Request) {
+		fake.set(r.PathValue("client"), r.PathValue("attribute"), "MIIgenerated")
+		writeJSON(w, http.StatusOK, certificateRepresentation{Certificate: "MIIgenerated", PrivateKey: "MIIprivate"})
+	})
+	mux.HandleFunc("POST /admin/realms/acme/clients/{client}/certificates/{attribute}/upload-certificate", func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		pem, _ := io.ReadAll(file)
+		certificate := strings.NewReplacer("-----BEGIN CERTIFICATE-----", "", "-----END CERTIFICATE-----", "", "\n", "").Replace(string(pem))
+		fake.set(r.PathValue("client"), r.PathValue("attribute"), certificate)
+		writeJSON(w, http.StatusOK, certificateRepresentation{Certificate: certificate})
+	})
+	return fake
+}
+
+func (f *fakeClientCertificates) get(client, attribute string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.certificates[client+"/"+attribute]
+}
+
+func (f *fakeClientCertificates) set(client, attribute, certificate string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.certificates[client+"/"+attribute] = certificate
+}
+
+func TestSamlClientCertificatesSettleAfterApply(t *testing.T) {
+	mux := http.NewServeMux()
+	fake := newFakeClientCertificates(mux)
+	server := newAdminAPIServer(t, mux)
+	urn := testURN("SamlClientCertificates", "saml-app")
+
+	for name, fields := range map[string]map[string]property.Value{
+		"generated": {"generateEncryptionCertificate": property.New(true)},
+		"uploaded":  {"signingCertificate": property.New("-----BEGIN CERTIFICATE-----\nMIIuploaded\n-----END CERTIFICATE-----\n")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			fields["realmId"], fields["clientId"] = property.New("acme"), property.New("saml-app")
+			inputs := property.NewMap(fields)
+			created, err := server.Create(p.CreateRequest{Urn: urn, Properties: inputs})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !created.Properties.Get("currentSigningCertificate").IsString() {
+				t.Error("the live signing certificate is not reported")
+			}
+
+			read, err := server.Read(p.ReadRequest{ID: created.ID, Urn: urn, Properties: created.Properties, Inputs: inputs})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for stage, state := range map[string]property.Map{"create": created.Properties, "refresh": read.Properties} {
+				diff, err := server.Diff(p.DiffRequest{ID: created.ID, Urn: urn, State: state, Inputs: inputs})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff.HasChanges {
+					t.Errorf("unchanged inputs diff after %s: %v", stage, diff.DetailedDiff)
+				}
+			}
+		})
+	}
+
+	// A managed certificate replaced outside of Pulumi
+	fake.set("saml-app", samlSigningCertificateAttribute, "MIIreplaced")
+	inputs := property.NewMap(map[string]property.Value{
+		"realmId":            property.New("acme"),
+		"clientId":           property.New("saml-app"),
+		"signingCertificate": property.New("-----BEGIN CERTIFICATE-----\nMIIuploaded\n-----END CERTIFICATE-----\n"),
+	})
+	read, err := server.Read(p.ReadRequest{ID: "acme/saml-app", Urn: urn, Properties: inputs, Inputs: inputs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ensureEqual(t, "drifted signingCertificate", "MIIreplaced", stringProperty(t, read.Properties, "signingCertificate"))
+}
@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// GetRealmSamlIdpDescriptor returns the SAML IdP metadata of a realm
+type GetRealmSamlIdpDescriptor struct{}
+
+type GetRealmSamlIdpDescriptorArgs struct {
+	RealmID string `pulumi:"realmId"`
+}
+
+// GetSamlClientSpMetadata returns the SAML SP metadata of a client
+type GetSamlClientSpMetadata struct{}
+
+type GetSamlClientSpMetadataArgs struct {
+	RealmID  string `pulumi:"realmId"`
+	ClientID string `pulumi:"clientId"`
+}
+
+// SamlMetadataResult holds a SAML metadata document
+type SamlMetadataResult struct {
+	Xml string `pulumi:"xml"`
+}
+
+// Annotate provides schema documentation for the getRealmSamlIdpDescriptor function
+func (g *GetRealmSamlIdpDescriptor) Annotate(a infer.Annotator) {
+	a.Describe(&g, "Returns the SAML IdP descriptor (EntityDescriptor XML) of a realm")
+}
+
+func (args *GetRealmSamlIdpDescriptorArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+}
+
+// Annotate provides schema documentation for the getSamlClientSpMetadata function
+func (g *GetSamlClientSpMetadata) Annotate(a infer.Annotator) {
+	a.Describe(&g, "Returns the SAML SP metadata (EntityDescriptor XML) of a SAML client")
+}
+
+func (args *GetSamlClientSpMetadataArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.ClientID, "The clientId (entity ID) of the SAML client")
+}
+
+func (result *SamlMetadataResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.Xml, "The metadata document")
+}
+
+func (g *GetRealmSamlIdpDescriptor) Invoke(ctx context.Context, req infer.FunctionRequest[GetRealmSamlIdpDescriptorArgs]) (infer.FunctionResponse[SamlMetadataResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[SamlMetadataResult]{}, err
+	}
+
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetHeader("Accept", "application/xml").
+		Get(realmURL(ctx, req.Input.RealmID, "protocol", "saml", "descriptor"))
+	if err := checkResponse(resp, err); err != nil {
+		return infer.FunctionResponse[SamlMetadataResult]{}, fmt.Errorf("failed to get SAML descriptor of realm %s: %w", req.Input.RealmID, err)
+	}
+
+	return infer.FunctionResponse[SamlMetadataResult]{
+		Output: SamlMetadataResult{Xml: resp.String()},
+	}, nil
+}
+
+func (g *GetSamlClientSpMetadata) Invoke(ctx context.Context, req infer.FunctionRequest[GetSamlClientSpMetadataArgs]) (infer.FunctionResponse[SamlMetadataResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[SamlMetadataResult]{}, err
+	}
+
+	idOfClient, err := clientUUID(ctx, client, token, req.Input.RealmID, req.Input.ClientID)
+	if err != nil {
+		return infer.FunctionResponse[SamlMetadataResult]{}, err
+	}
+
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetHeader("Accept", "*/*").
+		Get(adminRealmURL(ctx, req.Input.RealmID, "clients", idOfClient, "installation", "providers", "saml-sp-descriptor"))
+	if err := checkResponse(resp, err); err != nil {
+		return infer.FunctionResponse[SamlMetadataResult]{}, fmt.Errorf("failed to get SP metadata of client %s: %w", req.Input.ClientID, err)
+	}
+
+	return infer.FunctionResponse[SamlMetadataResult]{
+		Output: SamlMetadataResult{Xml: resp.String()},
+	}, nil
+}
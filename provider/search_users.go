@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// SearchUsers searches the users of a realm, transparently iterating over result pages
+type SearchUsers struct{}
+
+type SearchUsersArgs struct {
+	RealmID    string            `pulumi:"realmId"`
+	Search     *string           `pulumi:"search,optional"`
+	Attributes map[string]string `pulumi:"attributes,optional"`
+	Enabled    *bool             `pulumi:"enabled,optional"`
+	Offset     int               `pulumi:"offset,optional"`
+	Limit      *int              `pulumi:"limit,optional"`
+	PageSize   *int              `pulumi:"pageSize,optional"`
+}
+
+type SearchUsersResult struct {
+	Users []UserResult `pulumi:"users"`
+}
+
+// Annotate provides schema documentation for the searchUsers function
+func (s *SearchUsers) Annotate(a infer.Annotator) {
+	a.Describe(&s, "Searches the users of a realm. Keycloak result pages are fetched until the limit is reached")
+}
+
+func (args *SearchUsersArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.Search, "Substring matched against username, email, first and last name")
+	a.Describe(&args.Attributes, "Attribute values the users must have")
+	a.Describe(&args.Enabled, "Only return enabled or disabled users")
+	a.Describe(&args.Offset, "Number of matching users to skip")
+	a.Describe(&args.Limit, "Maximum number of users to return, at most 10000. When unset, all matching users are returned, "+
+		"and more than 10000 fail the call")
+	a.Describe(&args.PageSize, "Number of users fetched per admin API request, at most 1000. "+
+		"Larger pages need fewer requests, smaller ones put less load on Keycloak per request")
+
+	a.SetDefault(&args.PageSize, pageSize)
+}
+
+func (result *SearchUsersResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.Users, "The matching users")
+}
+
+func (s *SearchUsers) Invoke(ctx context.Context, req infer.FunctionRequest[SearchUsersArgs]) (infer.FunctionResponse[SearchUsersResult], error) {
+	if req.Input.Offset < 0 {
+		return infer.FunctionResponse[SearchUsersResult]{}, fmt.Errorf("offset must not be negative")
+	}
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[SearchUsersResult]{}, err
+	}
+
+	params := gocloak.GetUsersParams{
+		Search:  req.Input.Search,
+		Enabled: req.Input.Enabled,
+	}
+	if len(req.Input.Attributes) > 0 {
+		params.Q = gocloak.StringP(attributeQuery(req.Input.Attributes))
+	}
+
+	result := SearchUsersResult{Users: []UserResult{}}
+	err = paginateListing(req.Input.Offset, req.Input.Limit, req.Input.PageSize, func(first, max int) (int, error) {
+		params.First = gocloak.IntP(first)
+		params.Max = gocloak.IntP(max)
+		users, err := client.GetUsers(ctx, token, req.Input.RealmID, params)
+		if err != nil {
+			return 0, fmt.Errorf("failed to search users: %w", err)
+		}
+		for _, user := range users {
+			result.Users = append(result.Users, userResult(user))
+		}
+		return len(users), nil
+	})
+	if err != nil {
+		return infer.FunctionResponse[SearchUsersResult]{}, err
+	}
+
+	return infer.FunctionResponse[SearchUsersResult]{
+		Output: result,
+	}, nil
+}
+
+// attributeQuery builds the q parameter of the users endpoint, e.g. "department:eng team:platform"
+func attributeQuery(attributes map[string]string) string {
+	terms := make([]string, 0, len(attributes))
+	for key, value := range attributes {
+		terms = append(terms, key+":"+value)
+	}
+	sort.Strings(terms)
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// SendVerifyEmail sends the verify email action email to a user
+type SendVerifyEmail struct{}
+
+type SendVerifyEmailArgs struct {
+	RealmID     string  `pulumi:"realmId"`
+	UserID      string  `pulumi:"userId"`
+	ClientID    *string `pulumi:"clientId,optional"`
+	RedirectUri *string `pulumi:"redirectUri,optional"`
+}
+
+// UserActionResult is returned by functions that trigger an action on a user
+type UserActionResult struct {
+	UserID string `pulumi:"userId"`
+}
+
+// Annotate provides schema documentation for the sendVerifyEmail function
+func (s *SendVerifyEmail) Annotate(a infer.Annotator) {
+	a.Describe(&s, "Sends an email with a link to verify the email address of a user")
+}
+
+func (args *SendVerifyEmailArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.UserID, "The ID of the user")
+	a.Describe(&args.ClientID, "The clientId of the client the user is sent back to after verification")
+	a.Describe(&args.RedirectUri, "Where the user is redirected after verification. Requires clientId")
+}
+
+func (result *UserActionResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.UserID, "The ID of the user")
+}
+
+func (s *SendVerifyEmail) Invoke(ctx context.Context, req infer.FunctionRequest[SendVerifyEmailArgs]) (infer.FunctionResponse[UserActionResult], error) {
+	if req.Input.RedirectUri != nil && req.Input.ClientID == nil {
+		return infer.FunctionResponse[UserActionResult]{}, fmt.Errorf("redirectUri requires clientId")
+	}
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[UserActionResult]{}, err
+	}
+
+	err = client.SendVerifyEmail(ctx, token, req.Input.UserID, req.Input.RealmID, gocloak.SendVerificationMailParams{
+		ClientID:    req.Input.ClientID,
+		RedirectURI: req.Input.RedirectUri,
+	})
+	if err != nil {
+		return infer.FunctionResponse[UserActionResult]{}, fmt.Errorf("failed to send verify email to user %s: %w", req.Input.UserID, err)
+	}
+
+	return infer.FunctionResponse[UserActionResult]{
+		Output: UserActionResult{UserID: req.Input.UserID},
+	}, nil
+}
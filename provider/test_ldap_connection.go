@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// TestLdapConnection checks that Keycloak can connect and bind to an LDAP server
+type TestLdapConnection struct{}
+
+type TestLdapConnectionArgs struct {
+	RealmID           string  `pulumi:"realmId"`
+	Action            string  `pulumi:"action,optional"`
+	ConnectionUrl     string  `pulumi:"connectionUrl"`
+	BindDn            *string `pulumi:"bindDn,optional"`
+	BindCredential    *string `pulumi:"bindCredential,optional" provider:"secret"`
+	AuthType          *string `pulumi:"authType,optional"`
+	StartTls          *bool   `pulumi:"startTls,optional"`
+	UseTruststoreSpi  *string `pulumi:"useTruststoreSpi,optional"`
+	ConnectionTimeout *int    `pulumi:"connectionTimeout,optional"`
+	ComponentID       *string `pulumi:"componentId,optional"`
+}
+
+type TestLdapConnectionResult struct {
+	Success bool    `pulumi:"success"`
+	Error   *string `pulumi:"error,optional"`
+}
+
+// Annotate provides schema documentation for the testLdapConnection function
+func (t *TestLdapConnection) Annotate(a infer.Annotator) {
+	a.Describe(&t, "Tests the connection to, or authentication against, an LDAP server from Keycloak")
+}
+
+func (args *TestLdapConnectionArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.Action, "testConnection to only connect, or testAuthentication to also bind with the credentials")
+	a.Describe(&args.ConnectionUrl, "The LDAP URL, e.g. ldaps://ldap.example.com:636")
+	a.Describe(&args.BindDn, "DN used to bind, for testAuthentication")
+	a.Describe(&args.BindCredential, "Password used to bind, for testAuthentication")
+	a.Describe(&args.AuthType, "simple or none")
+	a.Describe(&args.StartTls, "Whether to use StartTLS")
+	a.Describe(&args.UseTruststoreSpi, "always or never")
+	a.Describe(&args.ConnectionTimeout, "Connection timeout in milliseconds")
+	a.Describe(&args.ComponentID, "ID of an existing LDAP provider, whose stored bind credential is used when bindCredential is not set")
+
+	a.SetDefault(&args.Action, "testConnection")
+}
+
+func (result *TestLdapConnectionResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.Success, "Whether the test succeeded")
+	a.Describe(&result.Error, "The error reported by Keycloak when the test failed")
+}
+
+func (t *TestLdapConnection) Invoke(ctx context.Context, req infer.FunctionRequest[TestLdapConnectionArgs]) (infer.FunctionResponse[TestLdapConnectionResult], error) {
+	switch req.Input.Action {
+	case "testConnection", "testAuthentication":
+	default:
+		return infer.FunctionResponse[TestLdapConnectionResult]{}, fmt.Errorf("invalid action %q: must be testConnection or testAuthentication", req.Input.Action)
+	}
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[TestLdapConnectionResult]{}, err
+	}
+
+	body := map[string]interface{}{
+		"action":        req.Input.Action,
+		"connectionUrl": req.Input.ConnectionUrl,
+	}
+	for key, value := range map[string]*string{
+		"bindDn":           req.Input.BindDn,
+		"bindCredential":   req.Input.BindCredential,
+		"authType":         req.Input.AuthType,
+		"useTruststoreSpi": req.Input.UseTruststoreSpi,
+		"componentId":      req.Input.ComponentID,
+	} {
+		if value != nil {
+			body[key] = *value
+		}
+	}
+	if req.Input.StartTls != nil {
+		body["startTls"] = fmt.Sprintf("%t", *req.Input.StartTls)
+	}
+	if req.Input.ConnectionTimeout != nil {
+		body["connectionTimeout"] = fmt.Sprintf("%d", *req.Input.ConnectionTimeout)
+	}
+
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetBody(body).
+		Post(adminRealmURL(ctx, req.Input.RealmID, "testLDAPConnection"))
+	if err != nil {
+		return infer.FunctionResponse[TestLdapConnectionResult]{}, fmt.Errorf("failed to test LDAP connection: %w", err)
+	}
+	if resp.IsError() {
+		message := checkResponse(resp, nil).Error()
+		return infer.FunctionResponse[TestLdapConnectionResult]{
+			Output: TestLdapConnectionResult{Success: false, Error: &message},
+		}, nil
+	}
+
+	return infer.FunctionResponse[TestLdapConnectionResult]{
+		Output: TestLdapConnectionResult{Success: true},
+	}, nil
+}
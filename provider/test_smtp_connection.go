@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// TestSmtpConnection sends a test email through the SMTP settings of a realm
+type TestSmtpConnection struct{}
+
+type TestSmtpConnectionArgs struct {
+	RealmID    string            `pulumi:"realmId"`
+	SmtpServer map[string]string `pulumi:"smtpServer,optional" provider:"secret"`
+}
+
+type TestSmtpConnectionResult struct {
+	Success bool    `pulumi:"success"`
+	Error   *string `pulumi:"error,optional"`
+}
+
+// Annotate provides schema documentation for the testSmtpConnection function
+func (t *TestSmtpConnection) Annotate(a infer.Annotator) {
+	a.Describe(&t, "Sends a test email using the SMTP settings of a realm. "+
+		"Keycloak delivers the email to the address of the admin user the provider authenticates as")
+}
+
+func (args *TestSmtpConnectionArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.SmtpServer, "SMTP settings to test (host, port, from, auth, user, password, ssl, starttls). Defaults to the realm's configured SMTP server")
+}
+
+func (result *TestSmtpConnectionResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.Success, "Whether the test email was sent")
+	a.Describe(&result.Error, "The error reported by Keycloak when the email could not be sent")
+}
+
+func (t *TestSmtpConnection) Invoke(ctx context.Context, req infer.FunctionRequest[TestSmtpConnectionArgs]) (infer.FunctionResponse[TestSmtpConnectionResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[TestSmtpConnectionResult]{}, err
+	}
+
+	settings := req.Input.SmtpServer
+	if settings == nil {
+		realm, err := client.GetRealm(ctx, token, req.Input.RealmID)
+		if err != nil {
+			return infer.FunctionResponse[TestSmtpConnectionResult]{}, fmt.Errorf("failed to get realm: %w", err)
+		}
+		if realm.SMTPServer == nil || len(*realm.SMTPServer) == 0 {
+			return infer.FunctionResponse[TestSmtpConnectionResult]{}, fmt.Errorf("realm %s has no SMTP server configured", req.Input.RealmID)
+		}
+		settings = *realm.SMTPServer
+	}
+
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetBody(settings).
+		Post(adminRealmURL(ctx, req.Input.RealmID, "testSMTPConnection"))
+	if err != nil {
+		return infer.FunctionResponse[TestSmtpConnectionResult]{}, fmt.Errorf("failed to test SMTP connection: %w", err)
+	}
+	if resp.IsError() {
+		// A failed delivery is a result, not a provider error, so programs can decide how to react
+		message := checkResponse(resp, nil).Error()
+		return infer.FunctionResponse[TestSmtpConnectionResult]{
+			Output: TestSmtpConnectionResult{Success: false, Error: &message},
+		}, nil
+	}
+
+	return infer.FunctionResponse[TestSmtpConnectionResult]{
+		Output: TestSmtpConnectionResult{Success: true},
+	}, nil
+}
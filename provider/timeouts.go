@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+)
+
+// withTimeouts bounds the create, update and delete operations of a provider by the customTimeouts resource option,
+// which the engine passes along with each request. Without the option, operations run as long as Keycloak takes
+func withTimeouts(provider p.Provider) p.Provider {
+	create, update, del := provider.Create, provider.Update, provider.Delete
+
+	provider.Create = func(ctx context.Context, req p.CreateRequest) (p.CreateResponse, error) {
+		ctx, cancel := withOperationTimeout(ctx, req.Timeout)
+		defer cancel()
+		resp, err := create(ctx, req)
+		return resp, timeoutError(ctx, "create", req.Timeout, err)
+	}
+	provider.Update = func(ctx context.Context, req p.UpdateRequest) (p.UpdateResponse, error) {
+		ctx, cancel := withOperationTimeout(ctx, req.Timeout)
+		defer cancel()
+		resp, err := update(ctx, req)
+		return resp, timeoutError(ctx, "update", req.Timeout, err)
+	}
+	provider.Delete = func(ctx context.Context, req p.DeleteRequest) error {
+		ctx, cancel := withOperationTimeout(ctx, req.Timeout)
+		defer cancel()
+		return timeoutError(ctx, "delete", req.Timeout, del(ctx, req))
+	}
+	return provider
+}
+
+func withOperationTimeout(ctx context.Context, seconds float64) (context.Context, context.CancelFunc) {
+	if seconds <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, secondsDuration(seconds))
+}
+
+// timeoutError explains an operation that failed because its timeout expired, since the admin API calls cut off by
+// the deadline only report a canceled request
+func timeoutError(ctx context.Context, operation string, seconds float64, err error) error {
+	if err == nil || seconds <= 0 || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	return fmt.Errorf("%s timed out after %s, raise the %s timeout with the customTimeouts resource option: %w",
+		operation, secondsDuration(seconds), operation, err)
+}
+
+func secondsDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
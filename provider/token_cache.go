@@ -0,0 +1,262 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	p "github.com/pulumi/pulumi-go-provider"
+)
+
+const (
+	// tokenExpiryMargin is how long before expiry a cached token is refreshed, unless tokenRefreshMargin is set
+	tokenExpiryMargin = 30 * time.Second
+	// backgroundRefreshTimeout bounds a refresh that no operation waits for
+	backgroundRefreshTimeout = 30 * time.Second
+	// supersededTokenRetention is how long after its expiry a refreshed token is still swapped for its successor
+	supersededTokenRetention = time.Hour
+)
+
+// tokenCacheEntry holds the last admin token obtained for one set of credentials
+type tokenCacheEntry struct {
+	mu             sync.Mutex
+	token          *gocloak.JWT
+	expires        time.Time
+	refreshExpires time.Time
+	margin         time.Duration
+	refresh        *time.Timer
+}
+
+// supersededToken is an access token replaced by a refresh, still held by operations that started before it
+type supersededToken struct {
+	entry   *tokenCacheEntry
+	expires time.Time
+}
+
+var (
+	tokenCacheMu     sync.Mutex
+	tokenCache       = map[string]*tokenCacheEntry{}
+	supersededTokens = map[string]supersededToken{}
+)
+
+// cachedToken returns the cached admin token for the configured credentials while it is valid. Near expiry
+// it is renewed with its refresh token when possible, otherwise by logging in again. Sharing the token across
+// resources saves a login per operation and keeps large deployments from locking out the admin account
+func cachedToken(ctx context.Context, config ProviderConfig, client *gocloak.GoCloak) (*gocloak.JWT, error) {
+	entry := tokenCacheEntryFor(config)
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	now := time.Now()
+	if entry.token != nil && now.Add(entry.margin).Before(entry.expires) {
+		return entry.token, nil
+	}
+
+	if entry.refreshable(now) {
+		token, err := config.refreshToken(ctx, client, entry.token.RefreshToken)
+		if err == nil {
+			p.GetLogger(ctx).Debug("refreshed the Keycloak admin token")
+			entry.store(config, client, token, now)
+			return token, nil
+		}
+		p.GetLogger(ctx).Warningf("failed to refresh the Keycloak admin token, logging in again: %v", err)
+	}
+
+	token, err := config.requestToken(ctx, client)
+	if err != nil {
+		entry.token = nil
+		return nil, err
+	}
+	p.GetLogger(ctx).Debugf("logged in to Keycloak realm %s", config.adminRealm())
+	entry.store(config, client, token, now)
+	return token, nil
+}
+
+// refreshable reports whether the cached token can still be renewed with its refresh token
+func (entry *tokenCacheEntry) refreshable(now time.Time) bool {
+	return entry.token != nil && entry.token.RefreshToken != "" && now.Add(entry.margin).Before(entry.refreshExpires)
+}
+
+// store caches a token, unless it carries no lifetime such as a pre-acquired access token, and schedules its
+// refresh. The token it replaces is recorded as superseded, see currentToken
+func (entry *tokenCacheEntry) store(config ProviderConfig, client *gocloak.GoCloak, token *gocloak.JWT, obtained time.Time) {
+	if entry.refresh != nil {
+		entry.refresh.Stop()
+		entry.refresh = nil
+	}
+	if entry.token != nil && entry.token.AccessToken != token.AccessToken {
+		supersede(entry, entry.token.AccessToken, entry.expires, obtained)
+	}
+	if token.ExpiresIn <= 0 {
+		entry.token = nil
+		return
+	}
+
+	lifetime := time.Duration(token.ExpiresIn) * time.Second
+	entry.token = token
+	entry.expires = obtained.Add(lifetime)
+	entry.refreshExpires = obtained.Add(time.Duration(token.RefreshExpiresIn) * time.Second)
+	// A margin beyond half the lifetime would renew short-lived tokens on every use
+	entry.margin = min(config.tokenRefreshMargin(), lifetime/2)
+
+	if entry.refreshable(obtained) {
+		entry.refresh = time.AfterFunc(time.Until(entry.expires.Add(-entry.margin)), func() {
+			entry.refreshInBackground(config, client, token)
+		})
+	}
+}
+
+// refreshInBackground renews a token shortly before it expires, so that operations in flight then never present
+// an expired token. Failures are left to the next operation, which refreshes or logs in again on its own
+func (entry *tokenCacheEntry) refreshInBackground(config ProviderConfig, client *gocloak.GoCloak, token *gocloak.JWT) {
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	now := time.Now()
+	if entry.token != token || !entry.refreshable(now) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), backgroundRefreshTimeout)
+	defer cancel()
+	refreshed, err := config.refreshToken(ctx, client, token.RefreshToken)
+	if err != nil {
+		return
+	}
+	entry.store(config, client, refreshed, now)
+}
+
+// supersede records that an access token was replaced, and forgets tokens that expired long ago
+func supersede(entry *tokenCacheEntry, accessToken string, expires, now time.Time) {
+	tokenCacheMu.Lock()
+	defer tokenCacheMu.Unlock()
+
+	supersededTokens[accessToken] = supersededToken{entry: entry, expires: expires}
+	for old, superseded := range supersededTokens {
+		if now.After(superseded.expires.Add(supersededTokenRetention)) {
+			delete(supersededTokens, old)
+		}
+	}
+}
+
+// currentToken returns the token that replaced an access token through a refresh, or the access token itself.
+// Operations obtain their token once, so this keeps long ones from presenting a token that expired meanwhile
+func currentToken(accessToken string) string {
+	tokenCacheMu.Lock()
+	superseded, ok := supersededTokens[accessToken]
+	tokenCacheMu.Unlock()
+	if !ok {
+		return accessToken
+	}
+
+	superseded.entry.mu.Lock()
+	defer superseded.entry.mu.Unlock()
+	if superseded.entry.token == nil {
+		return accessToken
+	}
+	return superseded.entry.token.AccessToken
+}
+
+// tokenCacheEntryFor returns the cache entry for the server and credentials of a configuration
+func tokenCacheEntryFor(config ProviderConfig) *tokenCacheEntry {
+	identity, _ := json.Marshal([]interface{}{
+		config.baseURL(), config.adminRealm(), config.Username, config.Password,
+		config.ClientID, config.ClientSecret, config.AccessToken, config.RefreshToken,
+	})
+	sum := sha256.Sum256(identity)
+	key := hex.EncodeToString(sum[:])
+
+	tokenCacheMu.Lock()
+	defer tokenCacheMu.Unlock()
+
+	entry, ok := tokenCache[key]
+	if !ok {
+		entry = &tokenCacheEntry{}
+		tokenCache[key] = entry
+	}
+	return entry
+}
+
+// invalidateToken drops the cached token if it is the one the server rejected, so that the next caller logs in again.
+// A token renewed meanwhile by a concurrent request is kept
+func invalidateToken(config ProviderConfig, rejected string) {
+	entry := tokenCacheEntryFor(config)
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if entry.token != nil && entry.token.AccessToken == rejected {
+		entry.token = nil
+	}
+}
+
+// reauthTransport sends admin API requests with the current token in place of one a refresh has superseded, and
+// retries a request once with a new token when the server rejects its token with a 401, e.g. because it was revoked
+type reauthTransport struct {
+	base  http.RoundTripper
+	renew func(ctx context.Context, rejected string) (string, error)
+}
+
+func (t *reauthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if presented, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer "); ok {
+		if current := currentToken(presented); current != presented {
+			req = req.Clone(req.Context())
+			req.Header.Set("Authorization", "Bearer "+current)
+		}
+	}
+
+	// resty's GetBody reads a pooled buffer that another request reuses once this one is sent, so the body to send
+	// again is copied while it is intact
+	if req.GetBody != nil && req.Body != nil && req.Body != http.NoBody {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(content)), nil
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// Token requests are not authenticated with a bearer token, and a consumed body cannot be sent again
+	rejected, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+		return resp, nil
+	}
+
+	// Release the response, and with it any request limiter slot, before logging in again
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	token, err := t.renew(req.Context(), rejected)
+	if err != nil {
+		return nil, fmt.Errorf("the Keycloak admin token was rejected and logging in again failed: %w", err)
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	retry.Header.Set("Authorization", "Bearer "+token)
+	p.GetLogger(req.Context()).Debugf("the Keycloak admin token was rejected, retrying %s %s with a new one", req.Method, req.URL.Path)
+	return t.base.RoundTrip(retry)
+}
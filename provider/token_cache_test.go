@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestReauthTransportRetriesWithTheOriginalBody(t *testing.T) {
+	// Like resty's, the body and GetBody share a buffer that is reused once the request is sent
+	pooled := bytes.NewBufferString(`{"realm":"acme"}`)
+	req, err := http.NewRequest(http.MethodPut, "http://keycloak.test/admin/realms/acme", pooled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(pooled.Bytes())), nil
+	}
+	req.Header.Set("Authorization", "Bearer rejected")
+
+	var sent []string
+	transport := &reauthTransport{
+		base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			sent = append(sent, string(body))
+			pooled.Reset()
+			pooled.WriteString(`{"realm":"other"}`)
+
+			status := http.StatusNoContent
+			if req.Header.Get("Authorization") == "Bearer rejected" {
+				status = http.StatusUnauthorized
+			}
+			return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(""))}, nil
+		}),
+		renew: func(context.Context, string) (string, error) {
+			return "renewed", nil
+		},
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ensureEqual(t, "status", http.StatusNoContent, resp.StatusCode)
+	ensureEqual(t, "requests", 2, len(sent))
+	ensureEqual(t, "retried body", `{"realm":"acme"}`, sent[len(sent)-1])
+}
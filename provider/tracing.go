@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer creates the spans of the provider. It is a no-op until setupTracing installs an exporter
+var tracer = otel.Tracer("github.com/raushan606/pulumi-qeyqloaq-provider")
+
+var (
+	tracingOnce     sync.Once
+	tracingErr      error
+	tracingProvider atomic.Pointer[sdktrace.TracerProvider]
+)
+
+// setupTracing exports spans over OTLP/gRPC to the given endpoint, once per provider process.
+// An http:// endpoint is contacted without TLS
+func setupTracing(ctx context.Context, endpoint string) error {
+	tracingOnce.Do(func() {
+		options := []otlptracegrpc.Option{}
+		if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+			options = append(options, otlptracegrpc.WithEndpoint(u.Host))
+			if u.Scheme == "http" {
+				options = append(options, otlptracegrpc.WithInsecure())
+			}
+		} else {
+			options = append(options, otlptracegrpc.WithEndpoint(endpoint))
+		}
+
+		exporter, err := otlptracegrpc.New(ctx, options...)
+		if err != nil {
+			tracingErr = fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+			return
+		}
+
+		provider := sdktrace.NewTracerProvider(
+			sdktrace.WithBatcher(exporter),
+			sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "pulumi-resource-"+Name))),
+		)
+		otel.SetTracerProvider(provider)
+		tracingProvider.Store(provider)
+	})
+	return tracingErr
+}
+
+// flushTracing exports the spans of a finished operation, since the engine may stop the provider
+// process before a batch would otherwise be sent
+func flushTracing(ctx context.Context) {
+	if provider := tracingProvider.Load(); provider != nil {
+		_ = provider.ForceFlush(context.WithoutCancel(ctx))
+	}
+}
+
+// withTracing wraps the resource operations and invokes of a provider in spans carrying the operation and
+// resource URN. The admin API calls they make become child spans, see tracingTransport
+func withTracing(provider p.Provider) p.Provider {
+	create, read, update, del, invoke := provider.Create, provider.Read, provider.Update, provider.Delete, provider.Invoke
+
+	provider.Create = func(ctx context.Context, req p.CreateRequest) (p.CreateResponse, error) {
+		ctx, span := startOperation(ctx, "Create", string(req.Urn))
+		resp, err := create(ctx, req)
+		endOperation(ctx, span, err)
+		return resp, err
+	}
+	provider.Read = func(ctx context.Context, req p.ReadRequest) (p.ReadResponse, error) {
+		ctx, span := startOperation(ctx, "Read", string(req.Urn))
+		resp, err := read(ctx, req)
+		endOperation(ctx, span, err)
+		return resp, err
+	}
+	provider.Update = func(ctx context.Context, req p.UpdateRequest) (p.UpdateResponse, error) {
+		ctx, span := startOperation(ctx, "Update", string(req.Urn))
+		resp, err := update(ctx, req)
+		endOperation(ctx, span, err)
+		return resp, err
+	}
+	provider.Delete = func(ctx context.Context, req p.DeleteRequest) error {
+		ctx, span := startOperation(ctx, "Delete", string(req.Urn))
+		err := del(ctx, req)
+		endOperation(ctx, span, err)
+		return err
+	}
+	provider.Invoke = func(ctx context.Context, req p.InvokeRequest) (p.InvokeResponse, error) {
+		ctx, span := startOperation(ctx, "Invoke", "")
+		span.SetAttributes(attribute.String("pulumi.function", string(req.Token)))
+		resp, err := invoke(ctx, req)
+		endOperation(ctx, span, err)
+		return resp, err
+	}
+	return provider
+}
+
+func startOperation(ctx context.Context, operation, urn string) (context.Context, trace.Span) {
+	ctx, span := tracer.Start(ctx, "keycloak."+operation)
+	span.SetAttributes(attribute.String("pulumi.operation", operation))
+	if urn != "" {
+		span.SetAttributes(attribute.String("pulumi.urn", urn))
+	}
+	return ctx, span
+}
+
+func endOperation(ctx context.Context, span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+	flushTracing(ctx)
+}
+
+// tracingTransport records a span for every admin API request, with its method, path and status
+type tracingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := tracer.Start(req.Context(), "keycloak "+req.Method, trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("http.method", req.Method),
+		attribute.String("http.url", req.URL.Scheme+"://"+req.URL.Host+req.URL.Path),
+	)
+
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, nil
+}
@@ -0,0 +1,399 @@
+package provider
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// UserBulkImport provisions many users at once through batched partial imports.
+// Users can be given as a structured list, a CSV document or a JSON document, or any combination of them.
+type UserBulkImport struct{}
+
+type UserBulkImportArgs struct {
+	RealmID          string           `pulumi:"realmId" provider:"replaceOnChanges"`
+	Users            []BulkImportUser `pulumi:"users,optional"`
+	Csv              *string          `pulumi:"csv,optional" provider:"secret"`
+	Json             *string          `pulumi:"json,optional" provider:"secret"`
+	BatchSize        int              `pulumi:"batchSize,optional"`
+	IfResourceExists string           `pulumi:"ifResourceExists,optional"`
+}
+
+// BulkImportUser describes a single user of a UserBulkImport
+type BulkImportUser struct {
+	Username          string            `pulumi:"username"`
+	Email             *string           `pulumi:"email,optional"`
+	FirstName         *string           `pulumi:"firstName,optional"`
+	LastName          *string           `pulumi:"lastName,optional"`
+	Enabled           *bool             `pulumi:"enabled,optional"`
+	EmailVerified     *bool             `pulumi:"emailVerified,optional"`
+	Attributes        map[string]string `pulumi:"attributes,optional"`
+	Password          *string           `pulumi:"password,optional" provider:"secret"`
+	TemporaryPassword *bool             `pulumi:"temporaryPassword,optional"`
+}
+
+type UserBulkImportState struct {
+	UserBulkImportArgs
+	UserIDs map[string]string `pulumi:"userIds"`
+	Count   int               `pulumi:"count"`
+}
+
+// Annotate provides schema documentation for the UserBulkImport resource
+func (u *UserBulkImport) Annotate(a infer.Annotator) {
+	a.Describe(&u, "Provisions many users in a realm using batched partial imports. Destroying the resource deletes the users it created, "+
+		"never those that already existed")
+}
+
+func (args *UserBulkImportArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.Users, "Users to import")
+	a.Describe(&args.Csv, "CSV document with a header row. Known columns are username, email, firstName, lastName, enabled, emailVerified and password; other columns become user attributes")
+	a.Describe(&args.Json, "JSON array of user representations as used by the Keycloak admin API")
+	a.Describe(&args.BatchSize, "Number of users sent per partial import call")
+	a.Describe(&args.IfResourceExists, "What to do when a user already exists: SKIP, OVERWRITE or FAIL")
+
+	a.SetDefault(&args.BatchSize, 500)
+	a.SetDefault(&args.IfResourceExists, "SKIP")
+}
+
+func (u *BulkImportUser) Annotate(a infer.Annotator) {
+	a.Describe(&u.Username, "The username")
+	a.Describe(&u.Email, "The email address")
+	a.Describe(&u.FirstName, "The first name")
+	a.Describe(&u.LastName, "The last name")
+	a.Describe(&u.Enabled, "Whether the user is enabled")
+	a.Describe(&u.EmailVerified, "Whether the email address is verified")
+	a.Describe(&u.Attributes, "Custom user attributes")
+	a.Describe(&u.Password, "Initial password of the user")
+	a.Describe(&u.TemporaryPassword, "Whether the user must change the password on first login")
+}
+
+func (state *UserBulkImportState) Annotate(a infer.Annotator) {
+	a.Describe(&state.UserIDs, "IDs of the users this resource created, keyed by username. Users that already existed are left out")
+	a.Describe(&state.Count, "Number of users this resource created")
+}
+
+func (u *UserBulkImport) Create(ctx context.Context, req infer.CreateRequest[UserBulkImportArgs]) (infer.CreateResponse[UserBulkImportState], error) {
+	if req.DryRun {
+		return infer.CreateResponse[UserBulkImportState]{
+			ID:     req.Inputs.RealmID,
+			Output: UserBulkImportState{UserBulkImportArgs: req.Inputs},
+		}, nil
+	}
+
+	state, err := applyUserBulkImport(ctx, req.Inputs, nil)
+	if err != nil {
+		return infer.CreateResponse[UserBulkImportState]{}, err
+	}
+
+	return infer.CreateResponse[UserBulkImportState]{
+		ID:     req.Inputs.RealmID,
+		Output: state,
+	}, nil
+}
+
+func (u *UserBulkImport) Update(ctx context.Context, req infer.UpdateRequest[UserBulkImportArgs, UserBulkImportState]) (infer.UpdateResponse[UserBulkImportState], error) {
+	if req.DryRun {
+		return infer.UpdateResponse[UserBulkImportState]{
+			Output: UserBulkImportState{UserBulkImportArgs: req.Inputs, UserIDs: req.State.UserIDs, Count: req.State.Count},
+		}, nil
+	}
+
+	state, err := applyUserBulkImport(ctx, req.Inputs, req.State.UserIDs)
+	if err != nil {
+		return infer.UpdateResponse[UserBulkImportState]{}, err
+	}
+
+	return infer.UpdateResponse[UserBulkImportState]{
+		Output: state,
+	}, nil
+}
+
+func (u *UserBulkImport) Delete(ctx context.Context, req infer.DeleteRequest[UserBulkImportState]) (infer.DeleteResponse, error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
+	for username, userID := range req.State.UserIDs {
+		if err := client.DeleteUser(ctx, token, req.State.RealmID, userID); err != nil && !isNotFound(err) {
+			return infer.DeleteResponse{}, fmt.Errorf("failed to delete user %s: %w", username, err)
+		}
+	}
+
+	return infer.DeleteResponse{}, nil
+}
+
+func (u *UserBulkImport) Read(ctx context.Context, req infer.ReadRequest[UserBulkImportArgs, UserBulkImportState]) (infer.ReadResponse[UserBulkImportArgs, UserBulkImportState], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.ReadResponse[UserBulkImportArgs, UserBulkImportState]{}, err
+	}
+
+	existing, err := listUserIDs(ctx, client, token, req.ID)
+	if err != nil {
+		if isNotFound(err) {
+			return infer.ReadResponse[UserBulkImportArgs, UserBulkImportState]{}, nil
+		}
+		return infer.ReadResponse[UserBulkImportArgs, UserBulkImportState]{}, err
+	}
+
+	state := req.State
+	state.RealmID = req.ID
+	state.UserIDs = make(map[string]string, len(req.State.UserIDs))
+	for username := range req.State.UserIDs {
+		if userID, ok := existing[username]; ok {
+			state.UserIDs[username] = userID
+		}
+	}
+	state.Count = len(state.UserIDs)
+
+	return infer.ReadResponse[UserBulkImportArgs, UserBulkImportState]{
+		ID:     req.ID,
+		Inputs: req.Inputs,
+		State:  state,
+	}, nil
+}
+
+// applyUserBulkImport imports the users in batches, deletes previously imported users that were removed
+// from the inputs and reads back the IDs of all managed users
+func applyUserBulkImport(ctx context.Context, args UserBulkImportArgs, previous map[string]string) (UserBulkImportState, error) {
+	switch args.IfResourceExists {
+	case "SKIP", "OVERWRITE", "FAIL":
+	default:
+		return UserBulkImportState{}, fmt.Errorf("invalid ifResourceExists %q: must be SKIP, OVERWRITE or FAIL", args.IfResourceExists)
+	}
+	if args.BatchSize <= 0 {
+		return UserBulkImportState{}, fmt.Errorf("batchSize must be positive")
+	}
+
+	users, err := args.representations()
+	if err != nil {
+		return UserBulkImportState{}, err
+	}
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return UserBulkImportState{}, err
+	}
+
+	// Only users this resource added are its own. Users that existed before, whether skipped or overwritten, are
+	// never recorded, so that destroying the resource does not delete them
+	created := make(map[string]bool, len(users))
+	for start := 0; start < len(users); start += args.BatchSize {
+		end := min(start+args.BatchSize, len(users))
+		var result partialImportResult
+		resp, err := client.GetRequestWithBearerAuth(ctx, token).
+			SetBody(map[string]interface{}{
+				"ifResourceExists": args.IfResourceExists,
+				"users":            users[start:end],
+			}).
+			SetResult(&result).
+			Post(adminRealmURL(ctx, args.RealmID, "partialImport"))
+		if err := checkResponse(resp, err); err != nil {
+			return UserBulkImportState{}, fmt.Errorf("failed to import users %d-%d: %w", start+1, end, err)
+		}
+		for _, resource := range result.Results {
+			if resource.ResourceType == "USER" && resource.Action == "ADDED" {
+				created[strings.ToLower(resource.ResourceName)] = true
+			}
+		}
+	}
+
+	managed := make(map[string]bool, len(users))
+	for _, user := range users {
+		username := user["username"].(string)
+		_, owned := previous[username]
+		managed[username] = owned || created[username]
+	}
+	for username, userID := range previous {
+		if managed[username] {
+			continue
+		}
+		if err := client.DeleteUser(ctx, token, args.RealmID, userID); err != nil && !isNotFound(err) {
+			return UserBulkImportState{}, fmt.Errorf("failed to delete user %s: %w", username, err)
+		}
+	}
+
+	existing, err := listUserIDs(ctx, client, token, args.RealmID)
+	if err != nil {
+		return UserBulkImportState{}, err
+	}
+
+	state := UserBulkImportState{
+		UserBulkImportArgs: args,
+		UserIDs:            make(map[string]string, len(managed)),
+	}
+	for username, owned := range managed {
+		if !owned {
+			continue
+		}
+		userID, ok := existing[username]
+		if !ok {
+			return UserBulkImportState{}, fmt.Errorf("user %s was not found after import", username)
+		}
+		state.UserIDs[username] = userID
+	}
+	state.Count = len(state.UserIDs)
+
+	return state, nil
+}
+
+// representations collects the users from all input sources as admin API user representations
+func (args UserBulkImportArgs) representations() ([]map[string]interface{}, error) {
+	var users []map[string]interface{}
+	seen := make(map[string]bool)
+	add := func(user map[string]interface{}) error {
+		username, _ := user["username"].(string)
+		if username == "" {
+			return fmt.Errorf("user #%d has no username", len(users)+1)
+		}
+		// Keycloak stores usernames in lower case
+		username = strings.ToLower(username)
+		if seen[username] {
+			return fmt.Errorf("duplicate username %s", username)
+		}
+		seen[username] = true
+		user["username"] = username
+		users = append(users, user)
+		return nil
+	}
+
+	for _, user := range args.Users {
+		if err := add(user.representation()); err != nil {
+			return nil, err
+		}
+	}
+
+	if args.Csv != nil {
+		reader := csv.NewReader(strings.NewReader(*args.Csv))
+		header, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV header: %w", err)
+		}
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CSV: %w", err)
+			}
+			if err := add(bulkImportUserFromRecord(header, record).representation()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if args.Json != nil {
+		var jsonUsers []map[string]interface{}
+		if err := json.Unmarshal([]byte(*args.Json), &jsonUsers); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON users: %w", err)
+		}
+		for _, user := range jsonUsers {
+			if err := add(user); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return users, nil
+}
+
+func bulkImportUserFromRecord(header, record []string) BulkImportUser {
+	var user BulkImportUser
+	for i, column := range header {
+		if i >= len(record) || record[i] == "" {
+			continue
+		}
+		value := record[i]
+		switch column {
+		case "username":
+			user.Username = value
+		case "email":
+			user.Email = &value
+		case "firstName":
+			user.FirstName = &value
+		case "lastName":
+			user.LastName = &value
+		case "enabled":
+			enabled, _ := strconv.ParseBool(value)
+			user.Enabled = &enabled
+		case "emailVerified":
+			emailVerified, _ := strconv.ParseBool(value)
+			user.EmailVerified = &emailVerified
+		case "password":
+			user.Password = &value
+		default:
+			if user.Attributes == nil {
+				user.Attributes = make(map[string]string)
+			}
+			user.Attributes[column] = value
+		}
+	}
+	return user
+}
+
+func (u BulkImportUser) representation() map[string]interface{} {
+	user := map[string]interface{}{
+		"username": u.Username,
+		"enabled":  u.Enabled == nil || *u.Enabled,
+	}
+	if u.Email != nil {
+		user["email"] = *u.Email
+	}
+	if u.FirstName != nil {
+		user["firstName"] = *u.FirstName
+	}
+	if u.LastName != nil {
+		user["lastName"] = *u.LastName
+	}
+	if u.EmailVerified != nil {
+		user["emailVerified"] = *u.EmailVerified
+	}
+	if len(u.Attributes) > 0 {
+		attributes := make(map[string][]string, len(u.Attributes))
+		for key, value := range u.Attributes {
+			attributes[key] = []string{value}
+		}
+		user["attributes"] = attributes
+	}
+	if u.Password != nil {
+		user["credentials"] = []map[string]interface{}{{
+			"type":      "password",
+			"value":     *u.Password,
+			"temporary": gocloak.PBool(u.TemporaryPassword),
+		}}
+	}
+	return user
+}
+
+// listUserIDs pages through all users of a realm and returns their IDs keyed by username
+func listUserIDs(ctx context.Context, client KeycloakClient, token, realmName string) (map[string]string, error) {
+	ids := make(map[string]string)
+	err := paginate(0, nil, pageSize, func(first, max int) (int, error) {
+		users, err := client.GetUsers(ctx, token, realmName, gocloak.GetUsersParams{
+			BriefRepresentation: gocloak.BoolP(true),
+			First:               gocloak.IntP(first),
+			Max:                 gocloak.IntP(max),
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to list users: %w", err)
+		}
+		for _, user := range users {
+			ids[gocloak.PString(user.Username)] = gocloak.PString(user.ID)
+		}
+		return len(users), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/property"
+)
+
+// fakeUsers serves the users of one realm and imports them as Keycloak's partial import does with SKIP
+type fakeUsers struct {
+	mu    sync.Mutex
+	users map[string]string // IDs keyed by username
+}
+
+func newFakeUsers(mux *http.ServeMux, existing ...string) *fakeUsers {
+	fake := &fakeUsers{users: map[string]string{}}
+	for _, username := range existing {
+		fake.users[username] = "id-" + username
+	}
+	mux.HandleFunc("POST /admin/realms/acme/partialImport", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Users []struct {
+				Username string `json:"username"`
+			} `json:"users"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		var result partialImportResult
+		for _, user := range body.Users {
+			action := "SKIPPED"
+			if _, ok := fake.users[user.Username]; !ok {
+				action = "ADDED"
+				fake.users[user.Username] = "id-" + user.Username
+			}
+			result.Results = append(result.Results, partialImportResource{Action: action, ResourceType: "USER", ResourceName: user.Username})
+		}
+		writeJSON(w, http.StatusOK, result)
+	})
+	mux.HandleFunc("GET /admin/realms/acme/users", func(w http.ResponseWriter, r *http.Request) {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		var users []map[string]string
+		if first, _ := strconv.Atoi(r.URL.Query().Get("first")); first == 0 {
+			for username, id := range fake.users {
+				users = append(users, map[string]string{"id": id, "username": username})
+			}
+		}
+		writeJSON(w, http.StatusOK, users)
+	})
+	mux.HandleFunc("DELETE /admin/realms/acme/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		for username, id := range fake.users {
+			if id == r.PathValue("id") {
+				delete(fake.users, username)
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return fake
+}
+
+func (f *fakeUsers) exists(username string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.users[username]
+	return ok
+}
+
+func TestUserBulkImportDeletesOnlyUsersItCreated(t *testing.T) {
+	mux := http.NewServeMux()
+	fake := newFakeUsers(mux, "bob")
+	server := newAdminAPIServer(t, mux)
+
+	urn := testURN("UserBulkImport", "users")
+	inputs := property.NewMap(map[string]property.Value{
+		"realmId":          property.New("acme"),
+		"batchSize":        property.New(500.0),
+		"ifResourceExists": property.New("SKIP"),
+		"users": property.New([]property.Value{
+			property.New(map[string]property.Value{"username": property.New("alice")}),
+			property.New(map[string]property.Value{"username": property.New("bob")}),
+		}),
+	})
+	created, err := server.Create(p.CreateRequest{Urn: urn, Properties: inputs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	userIDs := created.Properties.Get("userIds").AsMap()
+	ensureEqual(t, "recorded users", 1, userIDs.Len())
+	if _, ok := userIDs.GetOk("bob"); ok {
+		t.Error("a user that already existed was recorded")
+	}
+
+	if err := server.Delete(p.DeleteRequest{ID: created.ID, Urn: urn, Properties: created.Properties}); err != nil {
+		t.Fatal(err)
+	}
+	if fake.exists("alice") {
+		t.Error("the imported user alice was not deleted")
+	}
+	if !fake.exists("bob") {
+		t.Error("destroying the import deleted bob, who existed before")
+	}
+}
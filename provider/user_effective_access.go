@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// GetUserEffectiveRoles returns the realm and client roles a user has, with composite roles resolved
+type GetUserEffectiveRoles struct{}
+
+type GetUserEffectiveRolesArgs struct {
+	RealmID   string   `pulumi:"realmId"`
+	UserID    string   `pulumi:"userId"`
+	ClientIDs []string `pulumi:"clientIds,optional"`
+}
+
+type GetUserEffectiveRolesResult struct {
+	RealmRoles  []string            `pulumi:"realmRoles"`
+	ClientRoles map[string][]string `pulumi:"clientRoles"`
+}
+
+// GetUserGroups returns all groups a user is a member of
+type GetUserGroups struct{}
+
+type GetUserGroupsArgs struct {
+	RealmID string `pulumi:"realmId"`
+	UserID  string `pulumi:"userId"`
+}
+
+type GetUserGroupsResult struct {
+	Groups []UserGroup `pulumi:"groups"`
+}
+
+// UserGroup describes a group membership of a user
+type UserGroup struct {
+	GroupID string `pulumi:"groupId"`
+	Name    string `pulumi:"name"`
+	Path    string `pulumi:"path"`
+}
+
+// Annotate provides schema documentation for the getUserEffectiveRoles function
+func (g *GetUserEffectiveRoles) Annotate(a infer.Annotator) {
+	a.Describe(&g, "Returns the effective realm and client roles of a user, including roles granted through groups and composite roles")
+}
+
+func (args *GetUserEffectiveRolesArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.UserID, "The ID of the user")
+	a.Describe(&args.ClientIDs, "clientIds of the clients to resolve client roles for. All clients of the realm are checked when unset")
+}
+
+func (result *GetUserEffectiveRolesResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.RealmRoles, "Names of the effective realm roles, sorted")
+	a.Describe(&result.ClientRoles, "Names of the effective client roles keyed by clientId, for clients where the user has roles")
+}
+
+// Annotate provides schema documentation for the getUserGroups function
+func (g *GetUserGroups) Annotate(a infer.Annotator) {
+	a.Describe(&g, "Returns all groups a user is a direct member of")
+}
+
+func (args *GetUserGroupsArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.UserID, "The ID of the user")
+}
+
+func (result *GetUserGroupsResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.Groups, "The groups of the user")
+}
+
+func (g *UserGroup) Annotate(a infer.Annotator) {
+	a.Describe(&g.GroupID, "The ID of the group")
+	a.Describe(&g.Name, "The name of the group")
+	a.Describe(&g.Path, "The full path of the group")
+}
+
+func (g *GetUserEffectiveRoles) Invoke(ctx context.Context, req infer.FunctionRequest[GetUserEffectiveRolesArgs]) (infer.FunctionResponse[GetUserEffectiveRolesResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[GetUserEffectiveRolesResult]{}, err
+	}
+
+	realmRoles, err := client.GetCompositeRealmRolesByUserID(ctx, token, req.Input.RealmID, req.Input.UserID)
+	if err != nil {
+		return infer.FunctionResponse[GetUserEffectiveRolesResult]{}, fmt.Errorf("failed to get effective realm roles: %w", err)
+	}
+
+	clients := make(map[string]string)
+	if req.Input.ClientIDs != nil {
+		for _, clientID := range req.Input.ClientIDs {
+			idOfClient, err := clientUUID(ctx, client, token, req.Input.RealmID, clientID)
+			if err != nil {
+				return infer.FunctionResponse[GetUserEffectiveRolesResult]{}, err
+			}
+			clients[clientID] = idOfClient
+		}
+	} else {
+		all, err := client.GetClients(ctx, token, req.Input.RealmID, gocloak.GetClientsParams{})
+		if err != nil {
+			return infer.FunctionResponse[GetUserEffectiveRolesResult]{}, fmt.Errorf("failed to list clients: %w", err)
+		}
+		for _, c := range all {
+			clients[gocloak.PString(c.ClientID)] = gocloak.PString(c.ID)
+		}
+	}
+
+	result := GetUserEffectiveRolesResult{
+		RealmRoles:  roleNames(realmRoles),
+		ClientRoles: map[string][]string{},
+	}
+	for clientID, idOfClient := range clients {
+		roles, err := client.GetCompositeClientRolesByUserID(ctx, token, req.Input.RealmID, idOfClient, req.Input.UserID)
+		if err != nil {
+			return infer.FunctionResponse[GetUserEffectiveRolesResult]{}, fmt.Errorf("failed to get effective roles of client %s: %w", clientID, err)
+		}
+		if len(roles) > 0 {
+			result.ClientRoles[clientID] = roleNames(roles)
+		}
+	}
+
+	return infer.FunctionResponse[GetUserEffectiveRolesResult]{
+		Output: result,
+	}, nil
+}
+
+func (g *GetUserGroups) Invoke(ctx context.Context, req infer.FunctionRequest[GetUserGroupsArgs]) (infer.FunctionResponse[GetUserGroupsResult], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[GetUserGroupsResult]{}, err
+	}
+
+	result := GetUserGroupsResult{Groups: []UserGroup{}}
+	err = paginate(0, nil, pageSize, func(first, max int) (int, error) {
+		groups, err := client.GetUserGroups(ctx, token, req.Input.RealmID, req.Input.UserID, gocloak.GetGroupsParams{
+			First: gocloak.IntP(first),
+			Max:   gocloak.IntP(max),
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to get groups of user %s: %w", req.Input.UserID, err)
+		}
+		for _, group := range groups {
+			result.Groups = append(result.Groups, UserGroup{
+				GroupID: gocloak.PString(group.ID),
+				Name:    gocloak.PString(group.Name),
+				Path:    gocloak.PString(group.Path),
+			})
+		}
+		return len(groups), nil
+	})
+	if err != nil {
+		return infer.FunctionResponse[GetUserGroupsResult]{}, err
+	}
+
+	return infer.FunctionResponse[GetUserGroupsResult]{
+		Output: result,
+	}, nil
+}
+
+// roleNames returns the sorted names of the given roles
+func roleNames(roles []*gocloak.Role) []string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, gocloak.PString(role.Name))
+	}
+	sort.Strings(names)
+	return names
+}
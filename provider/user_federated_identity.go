@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// UserFederatedIdentity links an existing Keycloak user to an identity at an external identity provider.
+// Links cannot be modified in place, so any change replaces the link.
+type UserFederatedIdentity struct{}
+
+type UserFederatedIdentityArgs struct {
+	RealmID               string `pulumi:"realmId" provider:"replaceOnChanges"`
+	UserID                string `pulumi:"userId" provider:"replaceOnChanges"`
+	IdentityProviderAlias string `pulumi:"identityProviderAlias" provider:"replaceOnChanges"`
+	FederatedUserID       string `pulumi:"federatedUserId" provider:"replaceOnChanges"`
+	FederatedUsername     string `pulumi:"federatedUsername" provider:"replaceOnChanges"`
+}
+
+type UserFederatedIdentityState struct {
+	UserFederatedIdentityArgs
+}
+
+// Annotate provides schema documentation for the UserFederatedIdentity resource
+func (u *UserFederatedIdentity) Annotate(a infer.Annotator) {
+	a.Describe(&u, "Links a Keycloak user to their identity at an external identity provider")
+}
+
+func (args *UserFederatedIdentityArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm the user belongs to")
+	a.Describe(&args.UserID, "The ID of the Keycloak user")
+	a.Describe(&args.IdentityProviderAlias, "The alias of the identity provider")
+	a.Describe(&args.FederatedUserID, "The user ID at the identity provider")
+	a.Describe(&args.FederatedUsername, "The username at the identity provider")
+}
+
+func (u *UserFederatedIdentity) Create(ctx context.Context, req infer.CreateRequest[UserFederatedIdentityArgs]) (infer.CreateResponse[UserFederatedIdentityState], error) {
+	id := req.Inputs.RealmID + "/" + req.Inputs.UserID + "/" + req.Inputs.IdentityProviderAlias
+
+	if req.DryRun {
+		return infer.CreateResponse[UserFederatedIdentityState]{
+			ID:     id,
+			Output: UserFederatedIdentityState{req.Inputs},
+		}, nil
+	}
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.CreateResponse[UserFederatedIdentityState]{}, err
+	}
+
+	err = client.CreateUserFederatedIdentity(ctx, token, req.Inputs.RealmID, req.Inputs.UserID, req.Inputs.IdentityProviderAlias, gocloak.FederatedIdentityRepresentation{
+		IdentityProvider: &req.Inputs.IdentityProviderAlias,
+		UserID:           &req.Inputs.FederatedUserID,
+		UserName:         &req.Inputs.FederatedUsername,
+	})
+	if err != nil {
+		return infer.CreateResponse[UserFederatedIdentityState]{}, fmt.Errorf("failed to create federated identity: %w", err)
+	}
+
+	return infer.CreateResponse[UserFederatedIdentityState]{
+		ID:     id,
+		Output: UserFederatedIdentityState{req.Inputs},
+	}, nil
+}
+
+func (u *UserFederatedIdentity) Delete(ctx context.Context, req infer.DeleteRequest[UserFederatedIdentityState]) (infer.DeleteResponse, error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
+	err = client.DeleteUserFederatedIdentity(ctx, token, req.State.RealmID, req.State.UserID, req.State.IdentityProviderAlias)
+	if err != nil && !isNotFound(err) {
+		return infer.DeleteResponse{}, fmt.Errorf("failed to delete federated identity: %w", err)
+	}
+
+	return infer.DeleteResponse{}, nil
+}
+
+func (u *UserFederatedIdentity) Read(ctx context.Context, req infer.ReadRequest[UserFederatedIdentityArgs, UserFederatedIdentityState]) (infer.ReadResponse[UserFederatedIdentityArgs, UserFederatedIdentityState], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.ReadResponse[UserFederatedIdentityArgs, UserFederatedIdentityState]{}, err
+	}
+
+	parts, err := parseResourceID(req.ID, "<realm>/<userId>/<identityProviderAlias>")
+	if err != nil {
+		return infer.ReadResponse[UserFederatedIdentityArgs, UserFederatedIdentityState]{}, err
+	}
+	realmName, userID, alias := parts[0], parts[1], parts[2]
+
+	identities, err := client.GetUserFederatedIdentities(ctx, token, realmName, userID)
+	if err != nil {
+		if isNotFound(err) {
+			return infer.ReadResponse[UserFederatedIdentityArgs, UserFederatedIdentityState]{}, nil
+		}
+		return infer.ReadResponse[UserFederatedIdentityArgs, UserFederatedIdentityState]{}, fmt.Errorf("failed to get federated identities: %w", err)
+	}
+
+	for _, identity := range identities {
+		if gocloak.PString(identity.IdentityProvider) != alias {
+			continue
+		}
+
+		state := UserFederatedIdentityState{
+			UserFederatedIdentityArgs{
+				RealmID:               realmName,
+				UserID:                userID,
+				IdentityProviderAlias: alias,
+				FederatedUserID:       gocloak.PString(identity.UserID),
+				FederatedUsername:     gocloak.PString(identity.UserName),
+			},
+		}
+		return infer.ReadResponse[UserFederatedIdentityArgs, UserFederatedIdentityState]{
+			ID:     req.ID,
+			Inputs: state.UserFederatedIdentityArgs,
+			State:  state,
+		}, nil
+	}
+
+	// The link no longer exists
+	return infer.ReadResponse[UserFederatedIdentityArgs, UserFederatedIdentityState]{}, nil
+}
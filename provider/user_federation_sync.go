@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// SyncAllUsers triggers a full synchronization of a user federation provider
+type SyncAllUsers struct{}
+
+// SyncChangedUsers synchronizes the users changed since the last synchronization of a user federation provider
+type SyncChangedUsers struct{}
+
+type UserFederationSyncArgs struct {
+	RealmID     string `pulumi:"realmId"`
+	ComponentID string `pulumi:"componentId"`
+	Timeout     *int   `pulumi:"timeout,optional"`
+}
+
+type UserFederationSyncResult struct {
+	Added   int    `pulumi:"added"`
+	Updated int    `pulumi:"updated"`
+	Removed int    `pulumi:"removed"`
+	Failed  int    `pulumi:"failed"`
+	Ignored bool   `pulumi:"ignored"`
+	Status  string `pulumi:"status"`
+}
+
+// Annotate provides schema documentation for the syncAllUsers function
+func (s *SyncAllUsers) Annotate(a infer.Annotator) {
+	a.Describe(&s, "Imports all users from a user federation provider such as LDAP")
+}
+
+// Annotate provides schema documentation for the syncChangedUsers function
+func (s *SyncChangedUsers) Annotate(a infer.Annotator) {
+	a.Describe(&s, "Imports the users changed since the last synchronization of a user federation provider such as LDAP")
+}
+
+func (args *UserFederationSyncArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.ComponentID, "The ID of the user federation provider component")
+	a.Describe(&args.Timeout, "Seconds to wait for the synchronization to finish, unlimited by default")
+}
+
+func (result *UserFederationSyncResult) Annotate(a infer.Annotator) {
+	a.Describe(&result.Added, "Number of users added")
+	a.Describe(&result.Updated, "Number of users updated")
+	a.Describe(&result.Removed, "Number of users removed")
+	a.Describe(&result.Failed, "Number of users that failed to synchronize")
+	a.Describe(&result.Ignored, "Whether the synchronization was skipped, e.g. because another one was running")
+	a.Describe(&result.Status, "Summary reported by Keycloak")
+}
+
+func (s *SyncAllUsers) Invoke(ctx context.Context, req infer.FunctionRequest[UserFederationSyncArgs]) (infer.FunctionResponse[UserFederationSyncResult], error) {
+	return syncUserFederation(ctx, req.Input, "triggerFullSync")
+}
+
+func (s *SyncChangedUsers) Invoke(ctx context.Context, req infer.FunctionRequest[UserFederationSyncArgs]) (infer.FunctionResponse[UserFederationSyncResult], error) {
+	return syncUserFederation(ctx, req.Input, "triggerChangedUsersSync")
+}
+
+func syncUserFederation(ctx context.Context, args UserFederationSyncArgs, action string) (infer.FunctionResponse[UserFederationSyncResult], error) {
+	if args.Timeout != nil && *args.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(*args.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.FunctionResponse[UserFederationSyncResult]{}, err
+	}
+
+	var synchronization struct {
+		Added   int    `json:"added"`
+		Updated int    `json:"updated"`
+		Removed int    `json:"removed"`
+		Failed  int    `json:"failed"`
+		Ignored bool   `json:"ignored"`
+		Status  string `json:"status"`
+	}
+	resp, err := client.GetRequestWithBearerAuth(ctx, token).
+		SetQueryParam("action", action).
+		SetResult(&synchronization).
+		Post(adminRealmURL(ctx, args.RealmID, "user-storage", args.ComponentID, "sync"))
+	if err := checkResponse(resp, err); err != nil {
+		return infer.FunctionResponse[UserFederationSyncResult]{}, fmt.Errorf("failed to synchronize user federation provider %s: %w", args.ComponentID, err)
+	}
+
+	return infer.FunctionResponse[UserFederationSyncResult]{
+		Output: UserFederationSyncResult(synchronization),
+	}, nil
+}
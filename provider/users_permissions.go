@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// UsersPermissions enables fine-grained admin permissions for the users of a realm
+// and attaches policies to the individual permission scopes.
+type UsersPermissions struct{}
+
+type UsersPermissionsArgs struct {
+	RealmID                    string                `pulumi:"realmId" provider:"replaceOnChanges"`
+	ViewScope                  *AdminPermissionScope `pulumi:"viewScope,optional"`
+	ManageScope                *AdminPermissionScope `pulumi:"manageScope,optional"`
+	MapRolesScope              *AdminPermissionScope `pulumi:"mapRolesScope,optional"`
+	ManageGroupMembershipScope *AdminPermissionScope `pulumi:"manageGroupMembershipScope,optional"`
+	ImpersonateScope           *AdminPermissionScope `pulumi:"impersonateScope,optional"`
+	UserImpersonatedScope      *AdminPermissionScope `pulumi:"userImpersonatedScope,optional"`
+}
+
+type UsersPermissionsState struct {
+	UsersPermissionsArgs
+}
+
+// Annotate provides schema documentation for the UsersPermissions resource
+func (u *UsersPermissions) Annotate(a infer.Annotator) {
+	a.Describe(&u, "Fine-grained admin permissions for the users of a realm. Destroying the resource disables the permissions")
+}
+
+func (args *UsersPermissionsArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.RealmID, "The name of the realm")
+	a.Describe(&args.ViewScope, "Policies allowed to view users")
+	a.Describe(&args.ManageScope, "Policies allowed to manage users")
+	a.Describe(&args.MapRolesScope, "Policies allowed to map roles to users")
+	a.Describe(&args.ManageGroupMembershipScope, "Policies allowed to manage the group membership of users")
+	a.Describe(&args.ImpersonateScope, "Policies allowed to impersonate users")
+	a.Describe(&args.UserImpersonatedScope, "Policies describing which users can be impersonated")
+}
+
+// scopes returns the configured scopes keyed by Keycloak scope name
+func (args UsersPermissionsArgs) scopes() map[string]*AdminPermissionScope {
+	return map[string]*AdminPermissionScope{
+		"view":                    args.ViewScope,
+		"manage":                  args.ManageScope,
+		"map-roles":               args.MapRolesScope,
+		"manage-group-membership": args.ManageGroupMembershipScope,
+		"impersonate":             args.ImpersonateScope,
+		"user-impersonated":       args.UserImpersonatedScope,
+	}
+}
+
+func (u *UsersPermissions) Create(ctx context.Context, req infer.CreateRequest[UsersPermissionsArgs]) (infer.CreateResponse[UsersPermissionsState], error) {
+	if req.DryRun {
+		return infer.CreateResponse[UsersPermissionsState]{
+			ID:     req.Inputs.RealmID,
+			Output: UsersPermissionsState{req.Inputs},
+		}, nil
+	}
+
+	state, err := applyUsersPermissions(ctx, req.Inputs)
+	if err != nil {
+		return infer.CreateResponse[UsersPermissionsState]{}, err
+	}
+
+	return infer.CreateResponse[UsersPermissionsState]{
+		ID:     req.Inputs.RealmID,
+		Output: state,
+	}, nil
+}
+
+func (u *UsersPermissions) Update(ctx context.Context, req infer.UpdateRequest[UsersPermissionsArgs, UsersPermissionsState]) (infer.UpdateResponse[UsersPermissionsState], error) {
+	if req.DryRun {
+		return infer.UpdateResponse[UsersPermissionsState]{
+			Output: UsersPermissionsState{req.Inputs},
+		}, nil
+	}
+
+	state, err := applyUsersPermissions(ctx, req.Inputs)
+	if err != nil {
+		return infer.UpdateResponse[UsersPermissionsState]{}, err
+	}
+
+	return infer.UpdateResponse[UsersPermissionsState]{
+		Output: state,
+	}, nil
+}
+
+func (u *UsersPermissions) Delete(ctx context.Context, req infer.DeleteRequest[UsersPermissionsState]) (infer.DeleteResponse, error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
+	_, err = setManagementPermissions(ctx, client, token, adminRealmURL(ctx, req.State.RealmID, "users-management-permissions"), false)
+	if err != nil && !isNotFound(err) {
+		return infer.DeleteResponse{}, err
+	}
+
+	return infer.DeleteResponse{}, nil
+}
+
+func (u *UsersPermissions) Read(ctx context.Context, req infer.ReadRequest[UsersPermissionsArgs, UsersPermissionsState]) (infer.ReadResponse[UsersPermissionsArgs, UsersPermissionsState], error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return infer.ReadResponse[UsersPermissionsArgs, UsersPermissionsState]{}, err
+	}
+
+	permissionIDs, err := getManagementPermissions(ctx, client, token, adminRealmURL(ctx, req.ID, "users-management-permissions"))
+	if err != nil && !isNotFound(err) {
+		return infer.ReadResponse[UsersPermissionsArgs, UsersPermissionsState]{}, fmt.Errorf("failed to get users management permissions: %w", err)
+	}
+	if permissionIDs == nil {
+		return infer.ReadResponse[UsersPermissionsArgs, UsersPermissionsState]{}, nil
+	}
+
+	args := req.State.UsersPermissionsArgs
+	args.RealmID = req.ID
+	scopes := args.scopes()
+	if req.State.RealmID == "" {
+		scopes = importedScopes(scopes)
+	}
+	state, err := readUsersPermissionsState(ctx, client, token, args, permissionIDs, scopes)
+	if err != nil {
+		return infer.ReadResponse[UsersPermissionsArgs, UsersPermissionsState]{}, err
+	}
+
+	return infer.ReadResponse[UsersPermissionsArgs, UsersPermissionsState]{
+		ID:     req.ID,
+		Inputs: state.UsersPermissionsArgs,
+		State:  state,
+	}, nil
+}
+
+func applyUsersPermissions(ctx context.Context, args UsersPermissionsArgs) (UsersPermissionsState, error) {
+	client, token, err := login(ctx)
+	if err != nil {
+		return UsersPermissionsState{}, err
+	}
+
+	permissionIDs, err := setManagementPermissions(ctx, client, token, adminRealmURL(ctx, args.RealmID, "users-management-permissions"), true)
+	if err != nil {
+		return UsersPermissionsState{}, err
+	}
+
+	if err := applyScopePermissions(ctx, client, token, args.RealmID, permissionIDs, args.scopes()); err != nil {
+		return UsersPermissionsState{}, err
+	}
+
+	return readUsersPermissionsState(ctx, client, token, args, permissionIDs, args.scopes())
+}
+
+func readUsersPermissionsState(ctx context.Context, client KeycloakClient, token string, args UsersPermissionsArgs, permissionIDs map[string]string, configured map[string]*AdminPermissionScope) (UsersPermissionsState, error) {
+	scopes, err := readScopePermissions(ctx, client, token, args.RealmID, permissionIDs, configured)
+	if err != nil {
+		return UsersPermissionsState{}, err
+	}
+
+	return UsersPermissionsState{
+		UsersPermissionsArgs{
+			RealmID:                    args.RealmID,
+			ViewScope:                  scopes["view"],
+			ManageScope:                scopes["manage"],
+			MapRolesScope:              scopes["map-roles"],
+			ManageGroupMembershipScope: scopes["manage-group-membership"],
+			ImpersonateScope:           scopes["impersonate"],
+			UserImpersonatedScope:      scopes["user-impersonated"],
+		},
+	}, nil
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"net/http"
+	"testing"
+
+	p "github.com/pulumi/pulumi-go-provider"
+)
+
+// newFakePermissions serves fine-grained admin permissions that are enabled for the users and the groups of one realm.
+// Only the view scopes have a policy
+func newFakePermissions(mux *http.ServeMux) {
+	scopePermissions := map[string]any{"enabled": true, "scopePermissions": map[string]string{"view": "perm-view", "manage": "perm-manage"}}
+	mux.HandleFunc("GET /admin/realms/acme/users-management-permissions", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, scopePermissions)
+	})
+	mux.HandleFunc("GET /admin/realms/acme/groups/{group}/management/permissions", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, scopePermissions)
+	})
+	mux.HandleFunc("GET /admin/realms/acme/clients", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, []map[string]string{{"id": "realm-management-id", "clientId": realmManagementClientID}})
+	})
+	const policies = "/admin/realms/acme/clients/realm-management-id/authz/resource-server/policy/"
+	mux.HandleFunc("GET "+policies+"{id}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"id": r.PathValue("id"), "decisionStrategy": "UNANIMOUS"})
+	})
+	mux.HandleFunc("GET "+policies+"{id}/associatedPolicies", func(w http.ResponseWriter, r *http.Request) {
+		associated := []map[string]string{}
+		if r.PathValue("id") == "perm-view" {
+			associated = append(associated, map[string]string{"id": "admins"})
+		}
+		writeJSON(w, http.StatusOK, associated)
+	})
+}
+
+func TestPermissionsImportReadsEveryScope(t *testing.T) {
+	mux := http.NewServeMux()
+	newFakePermissions(mux)
+	server := newAdminAPIServer(t, mux)
+
+	for typ, id := range map[string]string{"UsersPermissions": "acme", "GroupPermissions": "acme/group-1"} {
+		t.Run(typ, func(t *testing.T) {
+			imported, err := server.Read(p.ReadRequest{ID: id, Urn: testURN(typ, "permissions")})
+			if err != nil {
+				t.Fatal(err)
+			}
+			view, ok := imported.Inputs.GetOk("viewScope")
+			if !ok || !view.IsMap() {
+				t.Fatalf("the view scope was not imported: %v", imported.Inputs)
+			}
+			policies := view.AsMap().Get("policies").AsArray()
+			ensureEqual(t, "view policies", 1, policies.Len())
+			ensureEqual(t, "view policy", "admins", policies.Get(0).AsString())
+			if _, ok := imported.Inputs.GetOk("manageScope"); !ok {
+				t.Error("the manage scope was not imported")
+			}
+		})
+	}
+}